@@ -0,0 +1,66 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configAccessors generates typed getters for all paths of the
+// PackageConfiguration of a package. Misspelled paths become compile errors:
+//
+//	base, err := directory.CurrencyOptionsBase(config.DefaultManager, store)
+//
+// Usage in the package directory:
+//
+//	//go:generate go run ../codegen/configAccessors/main.go -package directory -o config_accessors.go
+//
+// The config.Section literals will be read from the Go source files of the
+// directory, see codegen.ParseSections(), so the generator neither imports nor
+// compiles the package. An outdated or broken output file therefore cannot
+// prevent its own regeneration.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/corestoreio/csfw/codegen"
+)
+
+func main() {
+	pkg := flag.String("package", "", "Name of the package")
+	dir := flag.String("dir", ".", "Directory of the package")
+	out := flag.String("o", "", "Output file, default stdout")
+	flag.Parse()
+
+	if *pkg == "" {
+		log.Print("Missing package name")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ss, err := codegen.ParseSections(*dir, *out)
+	codegen.LogFatal(err)
+	if len(ss) == 0 {
+		log.Fatalf("No config.Section found in %s", *dir)
+	}
+
+	formatted, err := codegen.GenerateConfigAccessors(*pkg, ss)
+	if err != nil {
+		codegen.LogFatal(err, "\n%s\n", formatted)
+	}
+	if *out == "" {
+		os.Stdout.Write(formatted)
+		return
+	}
+	codegen.LogFatal(ioutil.WriteFile(*out, formatted, 0600))
+}
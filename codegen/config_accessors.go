@@ -0,0 +1,140 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+)
+
+// ConfigAccessor describes a typed getter for one path of a SectionSlice.
+type ConfigAccessor struct {
+	// Func name of the getter, the camelized path, e.g. CurrencyOptionsBase
+	Func string
+	// Path fully qualified path, e.g. currency/options/base
+	Path string
+	// Label of the Field for the doc comment
+	Label string
+	// Type is the Go return type: string, bool, int, float64, []string or []int
+	Type string
+	// Method of the config.Reader which returns Type, e.g. String
+	Method string
+	// Scope defines the config.ArgFunc: ScopeStore, ScopeWebsite or empty for
+	// the default scope. The getter for the default scope has no ScopeIDer argument.
+	Scope string
+}
+
+// ConfigAccessors creates the typed getters for all fields of the SectionSlice.
+// The return type depends on the FieldType, the SourceModel and the type of
+// the Default value. Fields of TypeButton, TypeLabel and TypeCustom don't have
+// a value and will be skipped.
+func ConfigAccessors(ss config.SectionSlice) []ConfigAccessor {
+	var cas []ConfigAccessor
+	for _, s := range ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				if f == nil || f.Type == nil {
+					continue
+				}
+				switch f.Type.Type() {
+				case config.TypeButton, config.TypeLabel, config.TypeCustom:
+					continue
+				}
+				p := s.ID + config.PS + g.ID + config.PS + f.ID
+				ca := ConfigAccessor{
+					Func:  Camelize(strings.Replace(p, config.PS, "_", -1)),
+					Path:  p,
+					Label: strings.Join(strings.Fields(f.Label), " "),
+				}
+				ca.Type, ca.Method = accessorType(f)
+				switch {
+				case f.Scope.Has(config.ScopeStoreID):
+					ca.Scope = "ScopeStore"
+				case f.Scope.Has(config.ScopeWebsiteID):
+					ca.Scope = "ScopeWebsite"
+				}
+				cas = append(cas, ca)
+			}
+		}
+	}
+	return cas
+}
+
+// accessorType returns the Go type and the config.Reader method of a Field.
+func accessorType(f *config.Field) (typ, method string) {
+	switch f.SourceModel.(type) {
+	case *config.SourceYesNo, *config.SourceEnableDisable:
+		return "bool", "Bool"
+	}
+	switch f.Default.(type) {
+	case []int:
+		return "[]int", "IntSlice"
+	case []string:
+		return "[]string", "StringSlice"
+	}
+	if f.Type.Type() == config.TypeMultiselect {
+		return "[]string", "StringSlice"
+	}
+	switch f.Default.(type) {
+	case bool:
+		return "bool", "Bool"
+	case int, int64:
+		return "int", "Int"
+	case float64:
+		return "float64", "Float64"
+	}
+	return "string", "String"
+}
+
+// GenerateConfigAccessors generates the Go source of the typed getters for the
+// package pkg, see ConfigAccessors().
+func GenerateConfigAccessors(pkg string, ss config.SectionSlice) ([]byte, error) {
+	return GenerateCode(pkg, tplConfigAccessors, struct {
+		Package   string
+		Accessors []ConfigAccessor
+	}{pkg, ConfigAccessors(ss)}, nil)
+}
+
+const tplConfigAccessors = `// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package {{ .Package }}
+
+// Auto generated by configAccessors from the PackageConfiguration. DO NOT EDIT.
+
+import "github.com/corestoreio/csfw/config"
+{{ range .Accessors }}
+// {{ .Func }} returns the value of the path {{ .Path }}{{ with .Label }} ({{ . }}){{ end }}
+{{ if .Scope }}// in the scope of s. A nil s returns the value of the default scope.
+func {{ .Func }}(cr config.Reader, s config.ScopeIDer) ({{ .Type }}, error) {
+	return cr.{{ .Method }}(config.Path("{{ .Path }}"), config.{{ .Scope }}(s))
+}
+{{ else }}// in the default scope.
+func {{ .Func }}(cr config.Reader) ({{ .Type }}, error) {
+	return cr.{{ .Method }}(config.Path("{{ .Path }}"))
+}
+{{ end }}{{ end }}`
@@ -0,0 +1,63 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var accessorTestSections = config.NewConfiguration(
+	&config.Section{
+		ID: "web",
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID: "cookie",
+				Fields: config.FieldSlice{
+					&config.Field{ID: "cookie_path", Label: "Cookie\n Path", Type: config.TypeText, Scope: config.ScopePermAll},
+					&config.Field{ID: "cookie_httponly", Type: config.TypeSelect, Scope: config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID), SourceModel: config.NewSourceYesNo()},
+					&config.Field{ID: "cookie_lifetime", Type: config.TypeText, Scope: config.NewScopePerm(config.ScopeDefaultID), Default: 3600},
+					&config.Field{ID: "ratio", Type: config.TypeText, Default: 0.5},
+					&config.Field{ID: "domains", Type: config.TypeMultiselect, Scope: config.ScopePermAll},
+					&config.Field{ID: "ids", Type: config.TypeMultiselect, Default: []int{1, 2}},
+					&config.Field{ID: "flush", Type: config.TypeButton},
+				},
+			},
+		},
+	},
+)
+
+func TestConfigAccessors(t *testing.T) {
+	assert.Exactly(t, []ConfigAccessor{
+		{Func: "WebCookieCookiePath", Path: "web/cookie/cookie_path", Label: "Cookie Path", Type: "string", Method: "String", Scope: "ScopeStore"},
+		{Func: "WebCookieCookieHttponly", Path: "web/cookie/cookie_httponly", Type: "bool", Method: "Bool", Scope: "ScopeWebsite"},
+		{Func: "WebCookieCookieLifetime", Path: "web/cookie/cookie_lifetime", Type: "int", Method: "Int"},
+		{Func: "WebCookieRatio", Path: "web/cookie/ratio", Type: "float64", Method: "Float64"},
+		{Func: "WebCookieDomains", Path: "web/cookie/domains", Type: "[]string", Method: "StringSlice", Scope: "ScopeStore"},
+		{Func: "WebCookieIds", Path: "web/cookie/ids", Type: "[]int", Method: "IntSlice"},
+	}, ConfigAccessors(accessorTestSections))
+}
+
+func TestGenerateConfigAccessors(t *testing.T) {
+	code, err := GenerateConfigAccessors("web", accessorTestSections)
+	assert.NoError(t, err, "%s", code)
+	have := string(code)
+	assert.Contains(t, have, "package web\n")
+	assert.Contains(t, have, "func WebCookieCookiePath(cr config.Reader, s config.ScopeIDer) (string, error) {\n\treturn cr.String(config.Path(\"web/cookie/cookie_path\"), config.ScopeStore(s))\n}\n")
+	assert.Contains(t, have, "func WebCookieCookieLifetime(cr config.Reader) (int, error) {\n\treturn cr.Int(config.Path(\"web/cookie/cookie_lifetime\"))\n}\n")
+	assert.NotContains(t, have, "flush")
+}
@@ -0,0 +1,331 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/juju/errgo"
+)
+
+// ParseSections reads the config.Section literals of the Go files in the
+// directory dir without compiling the package. Test files and the files in
+// exclude will be skipped, e.g. the output file of a generator which might be
+// outdated. Only ID, Label, Type, Scope, Default and the Yes/No source models
+// will be set in the fields, which is enough for ConfigAccessors(). Identifiers
+// in Default will be resolved to the package level constants and variables.
+func ParseSections(dir string, exclude ...string) (config.SectionSlice, error) {
+	fset := token.NewFileSet()
+	filter := func(fi os.FileInfo) bool {
+		if strings.HasSuffix(fi.Name(), "_test.go") {
+			return false
+		}
+		for _, e := range exclude {
+			if filepath.Base(e) == fi.Name() {
+				return false
+			}
+		}
+		return true
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+
+	sp := &sectionParser{fset: fset, values: make(map[string]ast.Expr)}
+	// ParseDir returns maps so sort the file names to keep the order of the sections stable
+	files := make(map[string]*ast.File)
+	var names []string
+	for _, pkg := range pkgs {
+		for n, f := range pkg.Files {
+			files[n] = f
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		sp.collectValues(files[n])
+	}
+	for _, n := range names {
+		ast.Inspect(files[n], sp.inspect)
+		if sp.err != nil {
+			return nil, sp.err
+		}
+	}
+	return sp.ss, nil
+}
+
+// sectionParser converts the AST of config.Section literals into a SectionSlice.
+type sectionParser struct {
+	fset *token.FileSet
+	// values contains the package level constants and variables
+	values map[string]ast.Expr
+	ss     config.SectionSlice
+	err    error
+}
+
+// collectValues adds the package level constants and variables of a file.
+func (sp *sectionParser) collectValues(f *ast.File) {
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || (gd.Tok != token.CONST && gd.Tok != token.VAR) {
+			continue
+		}
+		for _, s := range gd.Specs {
+			vs := s.(*ast.ValueSpec)
+			for i, n := range vs.Names {
+				if i < len(vs.Values) {
+					sp.values[n.Name] = vs.Values[i]
+				}
+			}
+		}
+	}
+}
+
+func (sp *sectionParser) inspect(n ast.Node) bool {
+	if sp.err != nil {
+		return false
+	}
+	cl, ok := n.(*ast.CompositeLit)
+	if !ok || typeName(cl.Type) != "Section" {
+		return true
+	}
+	s := &config.Section{}
+	for _, kv := range keyValues(cl) {
+		switch key(kv) {
+		case "ID":
+			s.ID = sp.string(kv.Value)
+		case "Groups":
+			s.Groups = sp.groups(kv.Value)
+		}
+	}
+	sp.ss = append(sp.ss, s)
+	return false
+}
+
+func (sp *sectionParser) groups(x ast.Expr) config.GroupSlice {
+	var gs config.GroupSlice
+	for _, e := range elements(x) {
+		g := &config.Group{}
+		for _, kv := range keyValues(e) {
+			switch key(kv) {
+			case "ID":
+				g.ID = sp.string(kv.Value)
+			case "Fields":
+				g.Fields = sp.fields(kv.Value)
+			}
+		}
+		gs = append(gs, g)
+	}
+	return gs
+}
+
+func (sp *sectionParser) fields(x ast.Expr) config.FieldSlice {
+	var fs config.FieldSlice
+	for _, e := range elements(x) {
+		f := &config.Field{}
+		for _, kv := range keyValues(e) {
+			switch key(kv) {
+			case "ID":
+				f.ID = sp.string(kv.Value)
+			case "Label":
+				f.Label = sp.string(kv.Value)
+			case "Type":
+				f.Type = sp.fieldType(kv.Value)
+			case "Scope":
+				f.Scope = sp.scope(kv.Value)
+			case "Default":
+				f.Default = sp.value(kv.Value, 0)
+			case "SourceModel":
+				switch callName(kv.Value) {
+				case "NewSourceYesNo":
+					f.SourceModel = config.NewSourceYesNo()
+				case "NewSourceEnableDisable":
+					f.SourceModel = config.NewSourceEnableDisable()
+				}
+			}
+		}
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+// fieldType returns one of the config.Type constants.
+func (sp *sectionParser) fieldType(x ast.Expr) config.FieldTyper {
+	name := typeName(x)
+	for ft := config.TypeButton; ft <= config.TypeTime; ft++ {
+		if ft.String() == name {
+			return ft
+		}
+	}
+	if name == "" {
+		name = callName(x) + "()"
+	}
+	sp.fail(x, "Unknown field type %s", name)
+	return nil
+}
+
+// scope returns config.ScopePermAll or a config.NewScopePerm() call.
+func (sp *sectionParser) scope(x ast.Expr) config.ScopePerm {
+	if typeName(x) == "ScopePermAll" {
+		return config.ScopePermAll
+	}
+	ce, ok := x.(*ast.CallExpr)
+	if !ok || typeName(ce.Fun) != "NewScopePerm" {
+		sp.fail(x, "Unknown scope")
+		return config.ScopePerm(0)
+	}
+	var sgs []config.ScopeGroup
+	for _, a := range ce.Args {
+		switch typeName(a) {
+		case "ScopeDefaultID":
+			sgs = append(sgs, config.ScopeDefaultID)
+		case "ScopeWebsiteID":
+			sgs = append(sgs, config.ScopeWebsiteID)
+		case "ScopeStoreID":
+			sgs = append(sgs, config.ScopeStoreID)
+		default:
+			sp.fail(a, "Unknown scope group %s", typeName(a))
+		}
+	}
+	return config.NewScopePerm(sgs...)
+}
+
+// value returns the Go value of a literal or of a package level constant or
+// variable. Values which cannot be resolved return nil.
+func (sp *sectionParser) value(x ast.Expr, depth int) interface{} {
+	switch e := x.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			s, _ := strconv.Unquote(e.Value)
+			return s
+		case token.INT:
+			i, _ := strconv.Atoi(e.Value)
+			return i
+		case token.FLOAT:
+			f, _ := strconv.ParseFloat(e.Value, 64)
+			return f
+		}
+	case *ast.Ident:
+		switch e.Name {
+		case "true", "false":
+			return e.Name == "true"
+		}
+		if v, ok := sp.values[e.Name]; ok && depth < 10 {
+			return sp.value(v, depth+1)
+		}
+	case *ast.CompositeLit:
+		switch typeName(e.Type) {
+		case "[]int":
+			is := []int{}
+			for _, el := range e.Elts {
+				if i, ok := sp.value(el, depth).(int); ok {
+					is = append(is, i)
+				}
+			}
+			return is
+		case "[]string":
+			ss := []string{}
+			for _, el := range e.Elts {
+				if s, ok := sp.value(el, depth).(string); ok {
+					ss = append(ss, s)
+				}
+			}
+			return ss
+		}
+	}
+	return nil
+}
+
+func (sp *sectionParser) string(x ast.Expr) string {
+	s, ok := sp.value(x, 0).(string)
+	if !ok {
+		sp.fail(x, "Expecting a string")
+	}
+	return s
+}
+
+func (sp *sectionParser) fail(x ast.Expr, format string, args ...interface{}) {
+	if sp.err == nil {
+		sp.err = errgo.Newf("%s: "+format, append([]interface{}{sp.fset.Position(x.Pos())}, args...)...)
+	}
+}
+
+// elements returns the elements of a slice literal.
+func elements(x ast.Expr) []ast.Expr {
+	if cl, ok := x.(*ast.CompositeLit); ok {
+		return cl.Elts
+	}
+	return nil
+}
+
+// keyValues returns the keyed elements of a struct literal or of a pointer to it.
+func keyValues(x ast.Expr) []*ast.KeyValueExpr {
+	if ue, ok := x.(*ast.UnaryExpr); ok && ue.Op == token.AND {
+		x = ue.X
+	}
+	cl, ok := x.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var kvs []*ast.KeyValueExpr
+	for _, e := range cl.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			kvs = append(kvs, kv)
+		}
+	}
+	return kvs
+}
+
+func key(kv *ast.KeyValueExpr) string {
+	if id, ok := kv.Key.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// typeName returns the name of an identifier without the package qualifier,
+// e.g. Section for config.Section, or the element type of a slice, e.g. []int.
+func typeName(x ast.Expr) string {
+	switch e := x.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "[]" + typeName(e.Elt)
+		}
+	}
+	return ""
+}
+
+// callName returns the name of the called function, e.g. NewSourceYesNo.
+func callName(x ast.Expr) string {
+	if ce, ok := x.(*ast.CallExpr); ok {
+		return typeName(ce.Fun)
+	}
+	return ""
+}
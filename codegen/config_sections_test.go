@@ -0,0 +1,86 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codegen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSectionsSource = `package web
+
+import "github.com/corestoreio/csfw/config"
+
+const defaultLifetime = 3600
+
+var PackageConfiguration = config.NewConfiguration(
+	&config.Section{
+		ID: "web",
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID: "cookie",
+				Fields: config.FieldSlice{
+					&config.Field{ID: "cookie_path", Label: "Cookie\n Path", Type: config.TypeText, Scope: config.ScopePermAll},
+					&config.Field{ID: "cookie_httponly", Type: config.TypeSelect, Scope: config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID), SourceModel: config.NewSourceYesNo()},
+					&config.Field{ID: "cookie_lifetime", Type: config.TypeText, Scope: config.NewScopePerm(config.ScopeDefaultID), Default: defaultLifetime},
+					&config.Field{ID: "ratio", Type: config.TypeText, Default: 0.5},
+					&config.Field{ID: "domains", Type: config.TypeMultiselect, Scope: config.ScopePermAll},
+					&config.Field{ID: "ids", Type: config.TypeMultiselect, Default: []int{1, 2}},
+					&config.Field{ID: "flush", Type: config.TypeButton},
+				},
+			},
+		},
+	},
+)
+`
+
+func TestParseSections(t *testing.T) {
+	dir, err := ioutil.TempDir("", "csfw_sections")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.go"), []byte(testSectionsSource), 0600))
+	// the outdated output file must not be parsed
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config_accessors.go"), []byte("package web\nfunc {"), 0600))
+
+	ss, err := ParseSections(dir, "config_accessors.go")
+	assert.NoError(t, err)
+	assert.Exactly(t, ConfigAccessors(accessorTestSections), ConfigAccessors(ss))
+
+	f, err := ss.FindFieldByPath("web", "cookie", "cookie_lifetime")
+	assert.NoError(t, err)
+	assert.Exactly(t, 3600, f.Default)
+	assert.Exactly(t, config.NewScopePerm(config.ScopeDefaultID), f.Scope)
+
+	_, err = ParseSections(dir)
+	assert.Error(t, err)
+}
+
+func TestParseSectionsUnknownType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "csfw_sections")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := "package web\nvar s = &config.Section{ID: `web`, Groups: config.GroupSlice{&config.Group{ID: `a`, Fields: config.FieldSlice{&config.Field{ID: `b`, Type: NewFieldTypeMap()}}}}}\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.go"), []byte(src), 0600))
+	_, err = ParseSections(dir)
+	assert.Contains(t, err.Error(), "config.go:2:")
+	assert.Contains(t, err.Error(), "Unknown field type NewFieldTypeMap")
+}
@@ -0,0 +1,292 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/directory"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/juju/errgo"
+)
+
+// errConfigUsage gets returned for unknown sub commands or missing arguments.
+var errConfigUsage = errors.New("Usage: cs config list|get|set|unset|diff [-scope scope] [-actor name] [arguments]")
+
+// notSet will be printed for paths without a value.
+const notSet = "<not set>"
+
+// packages contains the configuration of all packages.
+var packages = []config.SectionSlice{
+	directory.PackageConfiguration,
+	store.PackageConfiguration,
+}
+
+// configCmd contains the loaded configuration and the store hierarchy.
+type configCmd struct {
+	ss  config.SectionSlice
+	cm  *config.Manager
+	sm  *store.Manager
+	out io.Writer
+}
+
+// configCommand runs a sub command of cs config.
+func configCommand(args []string) error {
+	if len(args) == 0 {
+		return errConfigUsage
+	}
+	sub := args[0]
+	fs := flag.NewFlagSet("config "+sub, flag.ExitOnError)
+	scope := fs.String("scope", config.ScopeRangeDefault, "default, websites/<id>, groups/<id>, stores/<id> or a store code")
+	actor := fs.String("actor", "", "Records set and unset in the history table with this actor")
+	if err := fs.Parse(args[1:]); err != nil {
+		return errgo.Mask(err)
+	}
+
+	var ss config.SectionSlice
+	if err := ss.MergeMultiple(packages...); err != nil {
+		return errgo.Mask(err)
+	}
+	if sub == "list" {
+		return listPaths(os.Stdout, ss, fs.Arg(0))
+	}
+
+	db, dbrConn, err := csdb.Connect()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	defer db.Close()
+	dbrSess := dbrConn.NewSession(nil)
+
+	c, err := newConfigCmd(dbrSess, ss, os.Stdout)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+
+	switch {
+	case sub == "get" && fs.NArg() > 0:
+		sg, id, err := c.scope(*scope)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		return c.get(sg, id, fs.Args()...)
+	case sub == "set" && fs.NArg() == 2, sub == "unset" && fs.NArg() == 1:
+		sg, id, err := c.scope(*scope)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		var v interface{} // nil deletes the row
+		if sub == "set" {
+			v = fs.Arg(1)
+		}
+		if err := c.write(dbrSess, *actor, fs.Arg(0), v, sg, id); err != nil {
+			return errgo.Mask(err)
+		}
+		fmt.Fprintf(c.out, "Path %s has been %s in scope %s\n", fs.Arg(0), sub, *scope)
+		return nil
+	case sub == "diff" && fs.NArg() == 2:
+		return c.diff(fs.Arg(0), fs.Arg(1))
+	}
+	return errConfigUsage
+}
+
+// newConfigCmd loads the defaults of the SectionSlice, the table
+// core_config_data and all websites, groups and stores.
+func newConfigCmd(dbrSess dbr.SessionRunner, ss config.SectionSlice, out io.Writer) (*configCmd, error) {
+	cm := config.NewManager()
+	cm.ApplyDefaults(ss)
+	if err := cm.ApplyCoreConfigData(dbrSess); err != nil {
+		return nil, errgo.Mask(err)
+	}
+	sm := store.NewManager(store.NewStorageOption(), store.SetManagerConfig(cm))
+	if err := sm.ReInit(dbrSess); err != nil {
+		return nil, errgo.Mask(err)
+	}
+	cm.SetScopeHierarchy(sm)
+	return &configCmd{ss: ss, cm: cm, sm: sm, out: out}, nil
+}
+
+// listPaths prints all fields of the SectionSlice whose path starts with prefix.
+func listPaths(out io.Writer, ss config.SectionSlice, prefix string) error {
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tTYPE\tSCOPE\tDEFAULT")
+	for _, s := range ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				p := s.ID + config.PS + g.ID + config.PS + f.ID
+				if false == strings.HasPrefix(p, prefix) {
+					continue
+				}
+				var ft string
+				if f.Type != nil {
+					ft = f.Type.Type().String()
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p, ft, strings.Join(f.Scope.Human(), ","), formatValue(f, f.Default))
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// scope parses a scope argument. A store code will be resolved to its Store
+// which knows its website.
+func (c *configCmd) scope(spec string) (config.ScopeGroup, config.ScopeIDer, error) {
+	if spec == "" || spec == config.ScopeRangeDefault {
+		return config.ScopeDefaultID, nil, nil
+	}
+	if i := strings.Index(spec, config.PS); i > 0 {
+		sg := config.GetScopeGroup(spec[:i])
+		id, err := strconv.ParseInt(spec[i+1:], 10, 64)
+		if err != nil || sg == config.ScopeDefaultID {
+			return config.ScopeAbsentID, nil, errgo.Newf("Invalid scope %q", spec)
+		}
+		return sg, config.ScopeID(id), nil
+	}
+	s, err := c.sm.Store(config.ScopeCode(spec))
+	if err != nil {
+		return config.ScopeAbsentID, nil, errgo.Newf("Cannot find store %q: %s", spec, err)
+	}
+	return config.ScopeStoreID, s, nil
+}
+
+// lookup returns the effective value of a path or nil if it is not set.
+func (c *configCmd) lookup(path string, sg config.ScopeGroup, id config.ScopeIDer) (interface{}, config.ScopeGroup, error) {
+	v, vsg, err := c.cm.Lookup(config.Path(path), config.Scope(sg, id))
+	if err == config.ErrKeyNotFound {
+		return nil, config.ScopeAbsentID, nil
+	}
+	return v, vsg, err
+}
+
+// get prints the effective values of the paths and the scope they come from.
+func (c *configCmd) get(sg config.ScopeGroup, id config.ScopeIDer, paths ...string) error {
+	tw := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tVALUE\tFROM")
+	for _, p := range paths {
+		v, vsg, err := c.lookup(p, sg, id)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		from := ""
+		if v != nil {
+			from = vsg.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p, c.format(p, v), from)
+	}
+	return tw.Flush()
+}
+
+// write sets or with a nil value unsets a path in exactly one scope within a
+// transaction. Unsetting deletes the row of core_config_data. The Manager
+// serves as Reader for the BackendModels and receives the written value after
+// the commit.
+func (c *configCmd) write(dbrSess *dbr.Session, actor, path string, v interface{}, sg config.ScopeGroup, id config.ScopeIDer) error {
+	tx, err := dbrSess.Begin()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	dw := config.NewDBWriter(tx, config.SetDBWriterSections(c.ss), config.SetDBWriterManager(c.cm))
+	var w config.Writer = dw
+	if actor != "" {
+		w = config.NewHistoryWriter(tx, w, config.SetHistoryWriterActor(actor))
+	}
+	if err := w.Write(config.Path(path), config.Value(v), config.Scope(sg, id), config.NoBubble()); err != nil {
+		dw.Rollback()
+		return errgo.Mask(err)
+	}
+	return errgo.Mask(dw.Commit())
+}
+
+// diff prints all paths whose effective values differ between two scopes.
+func (c *configCmd) diff(specA, specB string) error {
+	sgA, idA, err := c.scope(specA)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	sgB, idB, err := c.scope(specB)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+
+	tw := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "PATH\t%s\t%s\n", specA, specB)
+	for _, p := range c.paths() {
+		va, _, err := c.lookup(p, sgA, idA)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		vb, _, err := c.lookup(p, sgB, idB)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		if (va == nil) != (vb == nil) || fmt.Sprint(va) != fmt.Sprint(vb) {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", p, c.format(p, va), c.format(p, vb))
+		}
+	}
+	return tw.Flush()
+}
+
+// paths returns the sorted paths of all fields and of all set values.
+func (c *configCmd) paths() []string {
+	unique := make(map[string]bool)
+	for _, s := range c.ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				unique[s.ID+config.PS+g.ID+config.PS+f.ID] = true
+			}
+		}
+	}
+	for _, k := range c.cm.AllKeys() {
+		// key: scope/scopeID/path
+		if parts := strings.SplitN(k, config.PS, 3); len(parts) == 3 {
+			unique[parts[2]] = true
+		}
+	}
+	paths := make([]string, 0, len(unique))
+	for p := range unique {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// format returns the printable value of a path. Values of TypeObscure fields
+// will be masked.
+func (c *configCmd) format(path string, v interface{}) string {
+	f, _ := c.ss.FindFieldByPath(path)
+	return formatValue(f, v)
+}
+
+// formatValue masks the values of TypeObscure fields. f can be nil.
+func formatValue(f *config.Field, v interface{}) string {
+	switch {
+	case v == nil:
+		return notSet
+	case f != nil && f.Type != nil && f.Type.Type() == config.TypeObscure:
+		return config.ObscureMask
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,147 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSections = config.NewConfiguration(
+	&config.Section{
+		ID: "web",
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID: "cookie",
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `web/cookie/cookie_path`,
+						ID:      "cookie_path",
+						Type:    config.TypeText,
+						Scope:   config.ScopePermAll,
+						Default: "/",
+					},
+					&config.Field{
+						// Path: `web/cookie/secret`,
+						ID:      "secret",
+						Type:    config.TypeObscure,
+						Scope:   config.NewScopePerm(config.ScopeDefaultID),
+						Default: "s3cr3t",
+					},
+				},
+			},
+		},
+	},
+)
+
+func newTestConfigCmd(t *testing.T) (*configCmd, *bytes.Buffer) {
+	cm := config.NewManager()
+	cm.ApplyDefaults(testSections)
+	assert.NoError(t, cm.Write(config.Path("web/cookie/cookie_path"), config.Value("/euro"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+	assert.NoError(t, cm.Write(config.Path("web/cookie/cookie_path"), config.Value("/at"), config.ScopeStore(config.ScopeID(2)), config.NoBubble()))
+	assert.NoError(t, cm.Write(config.Path("web/unsecure/base_url"), config.Value("http://de.io/"), config.ScopeStore(config.ScopeID(1)), config.NoBubble()))
+
+	sm := store.NewManager(store.NewStorageOption(
+		store.SetStorageWebsites(
+			&store.TableWebsite{WebsiteID: 1, Code: dbr.NullString{NullString: sql.NullString{String: "euro", Valid: true}}, Name: dbr.NullString{NullString: sql.NullString{String: "Europe", Valid: true}}, SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: true, Valid: true}}},
+		),
+		store.SetStorageGroups(
+			&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 2},
+		),
+		store.SetStorageStores(
+			&store.TableStore{StoreID: 1, Code: dbr.NullString{NullString: sql.NullString{String: "de", Valid: true}}, WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 2, Code: dbr.NullString{NullString: sql.NullString{String: "at", Valid: true}}, WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
+		),
+	), store.SetManagerConfig(cm))
+	cm.SetScopeHierarchy(sm)
+
+	var buf bytes.Buffer
+	return &configCmd{ss: testSections, cm: cm, sm: sm, out: &buf}, &buf
+}
+
+func TestListPaths(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, listPaths(&buf, testSections, "web/cookie/s"))
+	have := buf.String()
+	assert.Contains(t, have, "PATH")
+	assert.Contains(t, have, "web/cookie/secret")
+	assert.Contains(t, have, config.ObscureMask)
+	assert.NotContains(t, have, "s3cr3t")
+	assert.NotContains(t, have, "cookie_path")
+}
+
+func TestConfigCmdScope(t *testing.T) {
+	c, _ := newTestConfigCmd(t)
+	tests := []struct {
+		spec    string
+		wantSG  config.ScopeGroup
+		wantID  int64
+		wantErr bool
+	}{
+		{"", config.ScopeDefaultID, -1, false},
+		{"default", config.ScopeDefaultID, -1, false},
+		{"websites/1", config.ScopeWebsiteID, 1, false},
+		{"groups/1", config.ScopeGroupID, 1, false},
+		{"stores/2", config.ScopeStoreID, 2, false},
+		{"at", config.ScopeStoreID, 2, false},
+		{"stores/x", config.ScopeAbsentID, -1, true},
+		{"xyz/1", config.ScopeAbsentID, -1, true},
+		{"fr", config.ScopeAbsentID, -1, true},
+	}
+	for _, test := range tests {
+		sg, id, err := c.scope(test.spec)
+		assert.Exactly(t, test.wantSG, sg, "Spec %q", test.spec)
+		if test.wantErr {
+			assert.Error(t, err, "Spec %q", test.spec)
+			continue
+		}
+		assert.NoError(t, err, "Spec %q", test.spec)
+		if test.wantID < 0 {
+			assert.Nil(t, id)
+		} else {
+			assert.Exactly(t, test.wantID, id.ScopeID(), "Spec %q", test.spec)
+		}
+	}
+}
+
+func TestConfigCmdGet(t *testing.T) {
+	c, buf := newTestConfigCmd(t)
+	sg, id, err := c.scope("de")
+	assert.NoError(t, err)
+	assert.NoError(t, c.get(sg, id, "web/cookie/cookie_path", "web/cookie/secret", "web/unsecure/base_url", "a/b/c"))
+	have := buf.String()
+	assert.Regexp(t, `web/cookie/cookie_path\s+/euro\s+ScopeWebsite`, have)
+	assert.Regexp(t, `web/cookie/secret\s+\*+\s+ScopeDefault`, have)
+	assert.Regexp(t, `web/unsecure/base_url\s+http://de.io/\s+ScopeStore`, have)
+	assert.Regexp(t, `a/b/c\s+<not set>`, have)
+}
+
+func TestConfigCmdDiff(t *testing.T) {
+	c, buf := newTestConfigCmd(t)
+	assert.NoError(t, c.diff("de", "stores/2"))
+	have := buf.String()
+	assert.Regexp(t, `PATH\s+de\s+stores/2`, have)
+	assert.Regexp(t, `web/cookie/cookie_path\s+/euro\s+/at`, have)
+	assert.Regexp(t, `web/unsecure/base_url\s+http://de.io/\s+<not set>`, have)
+	assert.NotContains(t, have, "web/cookie/secret")
+
+	assert.Error(t, c.diff("de", "fr"))
+}
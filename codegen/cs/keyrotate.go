@@ -0,0 +1,79 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/juju/errgo"
+)
+
+const (
+	envKey    = "CS_ENCRYPTION_KEY"
+	envKeyNew = "CS_ENCRYPTION_KEY_NEW"
+)
+
+// newCrypter creates an encryption backend with the base64 encoded key from env.
+func newCrypter(env string) (*config.BackendEncryption, error) {
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(env))
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	if len(key) == 0 {
+		return nil, errgo.Newf("Missing environment variable %s", env)
+	}
+	be := config.NewBackendEncryption()
+	return be, be.SetKey(key)
+}
+
+// keyRotateCommand re-encrypts all encrypted values in the table
+// core_config_data within one transaction. The base64 encoded keys will be
+// read from the environment variables CS_ENCRYPTION_KEY (current key) and
+// CS_ENCRYPTION_KEY_NEW.
+func keyRotateCommand(_ []string) error {
+	from, err := newCrypter(envKey)
+	if err != nil {
+		return err
+	}
+	to, err := newCrypter(envKeyNew)
+	if err != nil {
+		return err
+	}
+
+	db, dbrConn, err := csdb.Connect()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	defer db.Close()
+
+	tx, err := dbrConn.NewSession(nil).Begin()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	rotated, err := config.RotateEncryptionKey(tx, from, to)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return errgo.Mask(err)
+	}
+	fmt.Fprintf(os.Stdout, "Re-encrypted %d values. Please restart the app with the new %s.\n", rotated, envKey)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cs is the command line tool for the operation of a CoreStore
+// installation. The database connection uses the environment variable CS_DSN.
+//
+// Usage:
+//
+//	cs config list [path prefix]
+//	cs config get [-scope default|websites/<id>|groups/<id>|stores/<id>|<store code>] path...
+//	cs config set [-scope ...] [-actor name] path value
+//	cs config unset [-scope ...] [-actor name] path
+//	cs config diff scope scope
+//	cs keyrotate
+//
+// A scope is either default, the scope of the table core_config_data with its
+// ID or a store code. get and diff print the effective values after the
+// fallback store -> website -> default. set and unset write only into the
+// given scope, unset deletes the row. With -actor the change will be recorded in the history table,
+// see config.HistoryWriter. New packages must be added to the variable packages.
+//
+// keyrotate re-encrypts all encrypted values in core_config_data within one
+// transaction. The base64 encoded keys will be read from the environment
+// variables CS_ENCRYPTION_KEY (current key) and CS_ENCRYPTION_KEY_NEW.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/corestoreio/csfw/codegen"
+)
+
+// commands maps the name of a command to its function which receives the
+// remaining arguments.
+var commands = map[string]func(args []string) error{
+	"config":    configCommand,
+	"keyrotate": keyRotateCommand,
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [arguments]\nCommands: %v\n", os.Args[0], names)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	codegen.LogFatal(cmd(os.Args[2:]))
+}
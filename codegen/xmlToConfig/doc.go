@@ -0,0 +1,50 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+package main converts the Magento2 system.xml and config.xml of a module into
+a Go file containing the PackageConfiguration of type config.SectionSlice.
+
+Usage
+
+	xmlToConfig -package contact -system Magento/Contact/etc/adminhtml/system.xml \
+		-config Magento/Contact/etc/config.xml -o contact/config.go
+
+The attributes showInDefault, showInWebsite and showInStore become the ScopePerm
+and the defaults of the config.xml become the Default of the Fields. Default
+paths without a field in the system.xml will be added as hidden configuration
+with config.VisibleNo. Nested groups will be flattened into a group whose ID
+joins the IDs with an underscore, e.g. the group wpp in paypal becomes paypal_wpp.
+A field with a config_path will be placed at the section and group of the
+config_path because the value will be read from there. The path of the
+system.xml remains as SystemPath comment.
+
+Source and backend models which have been already implemented in Go will be
+mapped, see the variables sourceModels and backendModels. All other models are
+nil and the Magento class remains as comment for porting, e.g.:
+
+	&config.Field{
+		// Path: `contact/contact/enabled`,
+		ID:           "enabled",
+		Label:        `Enable Contact Us`,
+		Type:         config.TypeSelect,
+		SortOrder:    10,
+		Visible:      config.VisibleYes,
+		Scope:        config.ScopePermAll,
+		Default:      true,
+		BackendModel: nil,                     // Magento\Contact\Model\System\Config\Backend\Links
+		SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
+	},
+*/
+package main
@@ -0,0 +1,534 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/corestoreio/csfw/codegen"
+	"github.com/juju/errgo"
+)
+
+const (
+	importConfig = "github.com/corestoreio/csfw/config"
+	importPrefix = "github.com/corestoreio/csfw/"
+)
+
+type (
+	// goModel is the Go constructor of an already implemented Magento model.
+	goModel struct {
+		pkg  string // package name, e.g. config
+		expr string // expression without the package, e.g. NewSourceYesNo()
+	}
+
+	// xmlSystem represents the system.xml
+	xmlSystem struct {
+		Sections []*xmlSection `xml:"system>section"`
+	}
+	xmlScope struct {
+		ShowInDefault string `xml:"showInDefault,attr"`
+		ShowInWebsite string `xml:"showInWebsite,attr"`
+		ShowInStore   string `xml:"showInStore,attr"`
+	}
+	xmlSection struct {
+		xmlScope
+		ID        string      `xml:"id,attr"`
+		SortOrder int         `xml:"sortOrder,attr"`
+		Label     string      `xml:"label"`
+		Groups    []*xmlGroup `xml:"group"`
+	}
+	xmlGroup struct {
+		xmlScope
+		ID        string      `xml:"id,attr"`
+		SortOrder int         `xml:"sortOrder,attr"`
+		Label     string      `xml:"label"`
+		Comment   string      `xml:"comment"`
+		Fields    []*xmlField `xml:"field"`
+		Groups    []*xmlGroup `xml:"group"`
+	}
+	xmlField struct {
+		xmlScope
+		ID            string `xml:"id,attr"`
+		Type          string `xml:"type,attr"`
+		SortOrder     int    `xml:"sortOrder,attr"`
+		Label         string `xml:"label"`
+		Comment       string `xml:"comment"`
+		ConfigPath    string `xml:"config_path"`
+		FrontendModel string `xml:"frontend_model"`
+		SourceModel   string `xml:"source_model"`
+		BackendModel  string `xml:"backend_model"`
+	}
+
+	// xmlNode represents any element of the config.xml
+	xmlNode struct {
+		XMLName xml.Name
+		Content string    `xml:",chardata"`
+		Nodes   []xmlNode `xml:",any"`
+	}
+
+	// defaultValue is a path with its value from the config.xml
+	defaultValue struct {
+		path  string
+		value string
+	}
+
+	tplData struct {
+		Package  string
+		Imports  []string
+		Sources  []string
+		Sections []*tplSection
+	}
+	tplSection struct {
+		Hidden    bool
+		ID        string
+		Label     string
+		SortOrder int
+		Scope     string
+		Groups    []*tplGroup
+	}
+	tplGroup struct {
+		ID        string
+		Label     string
+		Comment   string
+		SortOrder int
+		Scope     string
+		Fields    []*tplField
+	}
+	tplField struct {
+		// Path from where the value will be read, the config_path if set
+		Path string
+		// SystemPath is the path in the system.xml if it differs from Path
+		SystemPath   string
+		ID           string
+		Label        string
+		Comment      string
+		Type         string
+		TypeComment  string
+		SortOrder    int
+		Scope        string
+		Default      string
+		BackendModel string
+		BackendClass string
+		SourceModel  string
+		SourceClass  string
+	}
+)
+
+// sourceModels maps Magento source models to the Go implementations.
+var sourceModels = map[string]goModel{
+	`Magento\Config\Model\Config\Source\Yesno`:               {"config", "NewSourceYesNo()"},
+	`Magento\Config\Model\Config\Source\Enabledisable`:       {"config", "NewSourceEnableDisable()"},
+	`Magento\Directory\Model\Config\Source\Country`:          {"directory", "NewSourceCountry()"},
+	`Magento\Config\Model\Config\Source\Locale`:              {"directory", "NewSourceLocale()"},
+	`Magento\Config\Model\Config\Source\Locale\Timezone`:     {"directory", "NewSourceTimezone()"},
+	`Magento\Config\Model\Config\Source\Locale\Currency`:     {"directory", "NewSourceCurrency()"},
+	`Magento\Config\Model\Config\Source\Locale\Currency\All`: {"directory", "NewSourceCurrencyAll()"},
+	`Magento\Directory\Model\Config\Source\Country\Full`:     {"directory", "NewSourceCountry()"},
+}
+
+// backendModels maps Magento backend models to the Go implementations.
+var backendModels = map[string]goModel{
+	`Magento\Config\Model\Config\Backend\Encrypted`:                {"config", "DefaultEncryption"},
+	`Magento\Config\Model\Config\Backend\Currency\Base`:            {"directory", "NewBackendCurrencyBase()"},
+	`Magento\Config\Model\Config\Backend\Currency\DefaultCurrency`: {"directory", "NewBackendCurrencyBase()"},
+	`Magento\Config\Model\Config\Backend\Currency\Allow`:           {"directory", "NewBackendCurrencyAllow()"},
+}
+
+// fieldTypes maps the type attribute of a field to the config.FieldType.
+// Unknown types become config.TypeCustom.
+var fieldTypes = map[string]string{
+	"":              "TypeText",
+	"text":          "TypeText",
+	"textarea":      "TypeTextarea",
+	"editor":        "TypeTextarea",
+	"select":        "TypeSelect",
+	"allowspecific": "TypeSelect",
+	"multiselect":   "TypeMultiselect",
+	"obscure":       "TypeObscure",
+	"password":      "TypeObscure",
+	"image":         "TypeImage",
+	"time":          "TypeTime",
+	"label":         "TypeLabel",
+	"button":        "TypeButton",
+	"hidden":        "TypeHidden",
+}
+
+var regexInt = regexp.MustCompile(`^(0|-?[1-9][0-9]{0,17})$`)
+
+func main() {
+	pkg := flag.String("package", "", "Name of the Go package")
+	system := flag.String("system", "", "Path to the system.xml")
+	cfg := flag.String("config", "", "Path to the config.xml")
+	out := flag.String("o", "", "Output file, default stdout")
+	flag.Parse()
+
+	if *pkg == "" || (*system == "" && *cfg == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var sections []*xmlSection
+	var defaults []defaultValue
+	var sources []string
+	if *system != "" {
+		f, err := os.Open(*system)
+		codegen.LogFatal(err)
+		sections, err = parseSystem(f)
+		f.Close()
+		codegen.LogFatal(err, "File: %s", *system)
+		sources = append(sources, filepath.Base(*system))
+	}
+	if *cfg != "" {
+		f, err := os.Open(*cfg)
+		codegen.LogFatal(err)
+		defaults, err = parseDefaults(f)
+		f.Close()
+		codegen.LogFatal(err, "File: %s", *cfg)
+		sources = append(sources, filepath.Base(*cfg))
+	}
+
+	data := newTplData(*pkg, sections, defaults)
+	data.Sources = sources
+	formatted, err := generate(data)
+	if err != nil {
+		codegen.LogFatal(err, "\n%s\n", formatted)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(formatted)
+		return
+	}
+	codegen.LogFatal(ioutil.WriteFile(*out, formatted, 0600))
+}
+
+// generate renders the tplData into formatted Go source.
+func generate(data *tplData) ([]byte, error) {
+	return codegen.GenerateCode(data.Package, tplCode, data, template.FuncMap{
+		"goString": goString,
+	})
+}
+
+// parseSystem decodes the sections of a system.xml
+func parseSystem(r io.Reader) ([]*xmlSection, error) {
+	var sys xmlSystem
+	if err := xml.NewDecoder(r).Decode(&sys); err != nil {
+		return nil, errgo.Mask(err)
+	}
+	return sys.Sections, nil
+}
+
+// parseDefaults decodes the default scope of a config.xml into paths with their
+// values in document order. Elements below the third level will be encoded as
+// JSON object.
+func parseDefaults(r io.Reader) ([]defaultValue, error) {
+	var root xmlNode
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, errgo.Mask(err)
+	}
+	var dvs []defaultValue
+	for _, d := range root.Nodes {
+		if d.XMLName.Local != "default" {
+			continue
+		}
+		for _, s := range d.Nodes {
+			for _, g := range s.Nodes {
+				for _, f := range g.Nodes {
+					v := strings.TrimSpace(f.Content)
+					if len(f.Nodes) > 0 {
+						j, err := json.Marshal(f.toMap())
+						if err != nil {
+							return nil, errgo.Mask(err)
+						}
+						v = string(j)
+					}
+					dvs = append(dvs, defaultValue{
+						path:  s.XMLName.Local + "/" + g.XMLName.Local + "/" + f.XMLName.Local,
+						value: v,
+					})
+				}
+			}
+		}
+	}
+	return dvs, nil
+}
+
+// toMap converts the child nodes into a map for the JSON encoding.
+func (n xmlNode) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(n.Nodes))
+	for _, c := range n.Nodes {
+		if len(c.Nodes) > 0 {
+			m[c.XMLName.Local] = c.toMap()
+			continue
+		}
+		m[c.XMLName.Local] = strings.TrimSpace(c.Content)
+	}
+	return m
+}
+
+// tplBuilder collects the template data of the system.xml sections.
+type tplBuilder struct {
+	pkg     string
+	d       *tplData
+	imports map[string]bool
+	// dm maps the default paths to their values
+	dm map[string]string
+	// used contains the default paths which have a field
+	used map[string]bool
+}
+
+// newTplData converts the sections and defaults into the template data. Defaults
+// without a field will be appended as hidden sections. Nested groups will be
+// flattened and a field with a config_path will be placed at that path.
+func newTplData(pkg string, sections []*xmlSection, defaults []defaultValue) *tplData {
+	b := &tplBuilder{
+		pkg:     pkg,
+		d:       &tplData{Package: pkg},
+		imports: map[string]bool{importConfig: true},
+		dm:      make(map[string]string, len(defaults)),
+		used:    make(map[string]bool, len(defaults)),
+	}
+	for _, dv := range defaults {
+		b.dm[dv.path] = dv.value
+	}
+
+	for _, s := range sections {
+		ts := b.section(s.ID)
+		ts.Label = strings.TrimSpace(s.Label)
+		ts.SortOrder = s.SortOrder
+		ts.Scope = s.scopePerm()
+		for _, g := range s.Groups {
+			b.addGroup(ts, g, "")
+		}
+	}
+	for _, ts := range b.d.Sections {
+		groups := ts.Groups[:0]
+		for _, tg := range ts.Groups {
+			if len(tg.Fields) > 0 {
+				groups = append(groups, tg)
+			}
+		}
+		ts.Groups = groups
+	}
+
+	b.d.Sections = append(b.d.Sections, hiddenSections(defaults, b.used)...)
+
+	for i := range b.imports {
+		b.d.Imports = append(b.d.Imports, i)
+	}
+	sort.Strings(b.d.Imports)
+	return b.d
+}
+
+// section returns the section with the ID or appends a new one.
+func (b *tplBuilder) section(id string) *tplSection {
+	for _, ts := range b.d.Sections {
+		if ts.ID == id {
+			return ts
+		}
+	}
+	ts := &tplSection{ID: id, Scope: "config.NewScopePerm()"}
+	b.d.Sections = append(b.d.Sections, ts)
+	return ts
+}
+
+// group returns the group with the ID or appends a new one.
+func (ts *tplSection) group(id string) *tplGroup {
+	for _, tg := range ts.Groups {
+		if tg.ID == id {
+			return tg
+		}
+	}
+	tg := &tplGroup{ID: id, Scope: "config.NewScopePerm()"}
+	ts.Groups = append(ts.Groups, tg)
+	return tg
+}
+
+// addGroup adds the fields of g to the section. A nested group becomes a group
+// whose ID joins the IDs of its parents with an underscore, e.g. the group wpp
+// in paypal becomes paypal_wpp.
+func (b *tplBuilder) addGroup(ts *tplSection, g *xmlGroup, prefix string) {
+	id := prefix + g.ID
+	if prefix != "" {
+		log.Printf("Flattening nested group %s/%s", ts.ID, id)
+	}
+	tg := ts.group(id)
+	tg.Label = strings.TrimSpace(g.Label)
+	tg.Comment = strings.TrimSpace(g.Comment)
+	tg.SortOrder = g.SortOrder
+	tg.Scope = g.scopePerm()
+	for _, f := range g.Fields {
+		b.addField(ts, tg, f)
+	}
+	for _, ng := range g.Groups {
+		b.addGroup(ts, ng, id+"_")
+	}
+}
+
+// addField adds the field to the group. A field with a config_path will be
+// added to the section and group of the config_path because the value will be
+// read from there. Missing sections and groups will be created with the
+// settings of the system.xml group.
+func (b *tplBuilder) addField(ts *tplSection, tg *tplGroup, f *xmlField) {
+	path := ts.ID + "/" + tg.ID + "/" + f.ID
+	tf := &tplField{
+		Path:      path,
+		ID:        f.ID,
+		Label:     strings.TrimSpace(f.Label),
+		Comment:   strings.TrimSpace(f.Comment),
+		SortOrder: f.SortOrder,
+		Scope:     f.scopePerm(),
+	}
+	tf.Type, tf.TypeComment = fieldType(f)
+	tf.BackendModel, tf.BackendClass = b.model(backendModels, f.BackendModel)
+	tf.SourceModel, tf.SourceClass = b.model(sourceModels, f.SourceModel)
+
+	if cp := strings.TrimSpace(f.ConfigPath); cp != "" && cp != path {
+		p := strings.Split(cp, "/")
+		if len(p) != 3 || p[0] == "" || p[1] == "" || p[2] == "" {
+			log.Printf("Ignoring invalid config_path %q of %s", cp, path)
+		} else {
+			tf.SystemPath, tf.Path, tf.ID = path, cp, p[2]
+			cs := b.section(p[0])
+			if cs.Label == "" && cs != ts {
+				cs.Label, cs.SortOrder, cs.Scope = ts.Label, ts.SortOrder, ts.Scope
+			}
+			cg := cs.group(p[1])
+			if cg.Label == "" && cg != tg {
+				cg.Label, cg.SortOrder, cg.Scope = tg.Label, tg.SortOrder, tg.Scope
+			}
+			ts, tg = cs, cg
+		}
+	}
+
+	tf.Default = "nil"
+	if v, ok := b.dm[tf.Path]; ok {
+		b.used[tf.Path] = true
+		tf.Default = goDefault(v, tf.SourceModel == "config.NewSourceYesNo()" || tf.SourceModel == "config.NewSourceEnableDisable()")
+	}
+	tg.Fields = append(tg.Fields, tf)
+}
+
+// model returns the Go expression of an implemented Magento class or nil and
+// the class as comment.
+func (b *tplBuilder) model(models map[string]goModel, class string) (expr, comment string) {
+	class = strings.TrimSpace(class)
+	m, ok := models[class]
+	if !ok || m.expr == "" {
+		return "nil", class
+	}
+	if m.pkg == b.pkg {
+		return m.expr, class
+	}
+	b.imports[importPrefix+m.pkg] = true
+	return m.pkg + "." + m.expr, class
+}
+
+// hiddenSections creates the sections of all unused default values.
+func hiddenSections(defaults []defaultValue, used map[string]bool) []*tplSection {
+	var sections []*tplSection
+	var ts *tplSection
+	var tg *tplGroup
+	for _, dv := range defaults {
+		if used[dv.path] {
+			continue
+		}
+		p := strings.Split(dv.path, "/")
+		if ts == nil || ts.ID != p[0] {
+			ts = &tplSection{Hidden: true, ID: p[0]}
+			sections = append(sections, ts)
+			tg = nil
+		}
+		if tg == nil || tg.ID != p[1] {
+			tg = &tplGroup{ID: p[1]}
+			ts.Groups = append(ts.Groups, tg)
+		}
+		tg.Fields = append(tg.Fields, &tplField{
+			Path:    dv.path,
+			ID:      p[2],
+			Default: goDefault(dv.value, false),
+		})
+	}
+	return sections
+}
+
+// scopePerm returns the Go expression of the ScopePerm.
+func (s xmlScope) scopePerm() string {
+	var ids []string
+	if isTrue(s.ShowInDefault) {
+		ids = append(ids, "config.ScopeDefaultID")
+	}
+	if isTrue(s.ShowInWebsite) {
+		ids = append(ids, "config.ScopeWebsiteID")
+	}
+	if isTrue(s.ShowInStore) {
+		ids = append(ids, "config.ScopeStoreID")
+	}
+	if len(ids) == 3 {
+		return "config.ScopePermAll"
+	}
+	return "config.NewScopePerm(" + strings.Join(ids, ", ") + ")"
+}
+
+// fieldType returns the Go expression of the FieldType and a comment for
+// unknown types or a frontend model.
+func fieldType(f *xmlField) (expr, comment string) {
+	t, ok := fieldTypes[f.Type]
+	if !ok {
+		t = "TypeCustom"
+		comment = "@todo: " + f.Type
+	}
+	if fm := strings.TrimSpace(f.FrontendModel); fm != "" {
+		comment = strings.TrimPrefix(comment+" "+fm, " ")
+	}
+	return "config." + t, comment
+}
+
+// goDefault returns the Go expression of a default value. Yes/no values become
+// bool, integers stay integers and everything else becomes a string.
+func goDefault(v string, yesNo bool) string {
+	switch {
+	case yesNo && v == "1":
+		return "true"
+	case yesNo && v == "0":
+		return "false"
+	case regexInt.MatchString(v):
+		return v
+	}
+	return goString(v)
+}
+
+// goString returns a raw string literal or a quoted string if s contains a backtick.
+func goString(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+func isTrue(s string) bool {
+	return s == "1" || s == "true"
+}
@@ -0,0 +1,219 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSystemXML = `<?xml version="1.0"?>
+<config xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="urn:magento:module:Magento_Config:etc/system_file.xsd">
+    <system>
+        <section id="contact" translate="label" type="text" sortOrder="100" showInDefault="1" showInWebsite="1" showInStore="1">
+            <class>separator-top</class>
+            <label>Contacts</label>
+            <tab>general</tab>
+            <group id="contact" translate="label" type="text" sortOrder="10" showInDefault="1" showInWebsite="1" showInStore="1">
+                <label>Contact Us</label>
+                <field id="enabled" translate="label" type="select" sortOrder="10" showInDefault="1" showInWebsite="1" showInStore="1">
+                    <label>Enable Contact Us</label>
+                    <backend_model>Magento\Contact\Model\System\Config\Backend\Links</backend_model>
+                    <source_model>Magento\Config\Model\Config\Source\Yesno</source_model>
+                </field>
+            </group>
+            <group id="email" translate="label" type="text" sortOrder="50" showInDefault="1" showInWebsite="1">
+                <label>Email Options</label>
+                <comment><![CDATA[Use <b>sender</b>]]></comment>
+                <field id="recipient_email" translate="label" type="text" sortOrder="10" showInDefault="1" showInWebsite="1" showInStore="1">
+                    <label>Send Emails To</label>
+                </field>
+                <field id="password" translate="label" type="obscure" sortOrder="20" showInDefault="1">
+                    <label>Password</label>
+                    <backend_model>Magento\Config\Model\Config\Backend\Encrypted</backend_model>
+                </field>
+                <field id="export" type="Magento\OfflineShipping\Block\Adminhtml\Form\Field\Export" sortOrder="30" showInWebsite="1">
+                    <label>Export</label>
+                    <config_path>contact/general/export</config_path>
+                </field>
+            </group>
+        </section>
+    </system>
+</config>`
+
+const testConfigXML = `<?xml version="1.0"?>
+<config xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="urn:magento:framework:App/Config/etc/config.xsd">
+    <default>
+        <contact>
+            <contact>
+                <enabled>1</enabled>
+            </contact>
+            <email>
+                <recipient_email><![CDATA[hello@example.com]]></recipient_email>
+            </email>
+            <general>
+                <export>0</export>
+            </general>
+        </contact>
+        <catalog>
+            <product>
+                <flat>
+                    <max_index_count>64</max_index_count>
+                </flat>
+                <default_tax_group>2</default_tax_group>
+            </product>
+        </catalog>
+    </default>
+</config>`
+
+func TestParseDefaults(t *testing.T) {
+	dvs, err := parseDefaults(strings.NewReader(testConfigXML))
+	assert.NoError(t, err)
+	assert.Exactly(t, []defaultValue{
+		{"contact/contact/enabled", "1"},
+		{"contact/email/recipient_email", "hello@example.com"},
+		{"contact/general/export", "0"},
+		{"catalog/product/flat", `{"max_index_count":"64"}`},
+		{"catalog/product/default_tax_group", "2"},
+	}, dvs)
+}
+
+func TestNewTplData(t *testing.T) {
+	sections, err := parseSystem(strings.NewReader(testSystemXML))
+	assert.NoError(t, err)
+	dvs, err := parseDefaults(strings.NewReader(testConfigXML))
+	assert.NoError(t, err)
+
+	d := newTplData("contact", sections, dvs)
+	assert.Exactly(t, []string{"github.com/corestoreio/csfw/config"}, d.Imports)
+	assert.Len(t, d.Sections, 2)
+
+	s := d.Sections[0]
+	assert.Exactly(t, "config.ScopePermAll", s.Scope)
+	assert.Exactly(t, "config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID)", s.Groups[1].Scope)
+	assert.Exactly(t, "Use <b>sender</b>", s.Groups[1].Comment)
+
+	enabled := s.Groups[0].Fields[0]
+	assert.Exactly(t, "true", enabled.Default)
+	assert.Exactly(t, "config.TypeSelect", enabled.Type)
+	assert.Exactly(t, "config.NewSourceYesNo()", enabled.SourceModel)
+	assert.Exactly(t, "nil", enabled.BackendModel)
+	assert.Exactly(t, `Magento\Contact\Model\System\Config\Backend\Links`, enabled.BackendClass)
+
+	fields := s.Groups[1].Fields
+	assert.Exactly(t, "`hello@example.com`", fields[0].Default)
+	assert.Exactly(t, "config.TypeObscure", fields[1].Type)
+	assert.Exactly(t, "config.DefaultEncryption", fields[1].BackendModel)
+	assert.Exactly(t, "config.NewScopePerm(config.ScopeDefaultID)", fields[1].Scope)
+	assert.Exactly(t, "nil", fields[1].Default)
+	assert.Len(t, fields, 2)
+
+	assert.Exactly(t, "general", s.Groups[2].ID, "group of the config_path")
+	assert.Exactly(t, "Email Options", s.Groups[2].Label)
+	export := s.Groups[2].Fields[0]
+	assert.Exactly(t, "export", export.ID)
+	assert.Exactly(t, "contact/general/export", export.Path)
+	assert.Exactly(t, "contact/email/export", export.SystemPath)
+	assert.Exactly(t, "config.TypeCustom", export.Type)
+	assert.Exactly(t, `@todo: Magento\OfflineShipping\Block\Adminhtml\Form\Field\Export`, export.TypeComment)
+	assert.Exactly(t, "0", export.Default, "default of the config_path")
+
+	h := d.Sections[1]
+	assert.True(t, h.Hidden)
+	assert.Exactly(t, "catalog", h.ID)
+	assert.Exactly(t, "`{\"max_index_count\":\"64\"}`", h.Groups[0].Fields[0].Default)
+	assert.Exactly(t, "2", h.Groups[0].Fields[1].Default)
+}
+
+func TestNewTplDataImports(t *testing.T) {
+	sections, err := parseSystem(strings.NewReader(`<config><system><section id="currency"><group id="options">
+		<field id="base" type="select"><source_model>Magento\Config\Model\Config\Source\Locale\Currency</source_model>
+		<backend_model>Magento\Config\Model\Config\Backend\Currency\Base</backend_model></field>
+		</group></section></system></config>`))
+	assert.NoError(t, err)
+
+	d := newTplData("currency", sections, nil)
+	assert.Exactly(t, []string{"github.com/corestoreio/csfw/config", "github.com/corestoreio/csfw/directory"}, d.Imports)
+	assert.Exactly(t, "directory.NewSourceCurrency()", d.Sections[0].Groups[0].Fields[0].SourceModel)
+
+	d = newTplData("directory", sections, nil)
+	assert.Exactly(t, []string{"github.com/corestoreio/csfw/config"}, d.Imports)
+	assert.Exactly(t, "NewBackendCurrencyBase()", d.Sections[0].Groups[0].Fields[0].BackendModel)
+	assert.Exactly(t, "config.NewScopePerm()", d.Sections[0].Scope)
+}
+
+func TestNewTplDataNestedGroups(t *testing.T) {
+	sections, err := parseSystem(strings.NewReader(`<config><system><section id="payment">
+		<group id="paypal" sortOrder="10" showInDefault="1"><label>PayPal</label>
+			<group id="wpp" sortOrder="20" showInDefault="1" showInWebsite="1"><label>Website Payments Pro</label>
+				<field id="active" type="select"><label>Enable</label></field>
+				<group id="sandbox"><field id="flag"><config_path>payment/paypal_sandbox/flag</config_path></field></group>
+				<field id="title"><config_path>payment/paypal_wpp/title/extra</config_path></field>
+			</group>
+		</group>
+		</section></system></config>`))
+	assert.NoError(t, err)
+
+	d := newTplData("payment", sections, []defaultValue{{"payment/paypal_sandbox/flag", "1"}})
+	assert.Len(t, d.Sections, 1)
+	groups := d.Sections[0].Groups
+	assert.Len(t, groups, 2, "the empty group paypal will be removed")
+
+	assert.Exactly(t, "paypal_wpp", groups[0].ID)
+	assert.Exactly(t, "Website Payments Pro", groups[0].Label)
+	assert.Exactly(t, 20, groups[0].SortOrder)
+	assert.Exactly(t, "config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID)", groups[0].Scope)
+	assert.Len(t, groups[0].Fields, 2)
+	assert.Exactly(t, "payment/paypal_wpp/active", groups[0].Fields[0].Path)
+	assert.Exactly(t, "payment/paypal_wpp/title", groups[0].Fields[1].Path, "invalid config_path will be ignored")
+	assert.Exactly(t, "", groups[0].Fields[1].SystemPath)
+
+	assert.Exactly(t, "paypal_sandbox", groups[1].ID, "the empty group paypal_wpp_sandbox will be removed")
+	assert.Exactly(t, "payment/paypal_sandbox/flag", groups[1].Fields[0].Path)
+	assert.Exactly(t, "payment/paypal_wpp_sandbox/flag", groups[1].Fields[0].SystemPath)
+	assert.Exactly(t, "1", groups[1].Fields[0].Default)
+}
+
+func TestGenerate(t *testing.T) {
+	sections, err := parseSystem(strings.NewReader(testSystemXML))
+	assert.NoError(t, err)
+	dvs, err := parseDefaults(strings.NewReader(testConfigXML))
+	assert.NoError(t, err)
+	d := newTplData("contact", sections, dvs)
+	d.Sources = []string{"system.xml", "config.xml"}
+
+	code, err := generate(d)
+	assert.NoError(t, err, "%s", code)
+	have := string(code)
+	assert.Contains(t, have, "package contact\n")
+	assert.Contains(t, have, "// Auto generated by xmlToConfig from system.xml config.xml\n")
+	assert.Contains(t, have, "\t\t\t\t\t\t// Path: `contact/contact/enabled`,\n")
+	assert.Contains(t, have, "SourceModel:  config.NewSourceYesNo(), // Magento\\Config\\Model\\Config\\Source\\Yesno\n")
+	assert.Contains(t, have, "Comment:   `Use <b>sender</b>`,\n")
+	assert.Contains(t, have, "// Path: `contact/general/export`,\n")
+	assert.Contains(t, have, "// SystemPath: `contact/email/export`,\n")
+	assert.Contains(t, have, "\t// Hidden Configuration\n")
+	assert.Contains(t, have, "Default: 2,\n")
+}
+
+func TestGoString(t *testing.T) {
+	assert.Exactly(t, "`a\"b`", goString(`a"b`))
+	assert.Exactly(t, `"a`+"`"+`b"`, goString("a`b"))
+	assert.Exactly(t, "true", goDefault("1", true))
+	assert.Exactly(t, "1", goDefault("1", false))
+	assert.Exactly(t, "`007`", goDefault("007", false))
+}
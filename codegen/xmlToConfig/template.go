@@ -0,0 +1,94 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+const tplCode = `// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package {{ .Package }}
+
+import (
+{{ range .Imports }}	"{{ . }}"
+{{ end }})
+
+// Auto generated by xmlToConfig from{{ range .Sources }} {{ . }}{{ end }}
+
+// PackageConfiguration global configuration options for this package.
+// Used in Frontend and Backend.
+var PackageConfiguration = config.NewConfiguration(
+{{ range .Sections }}{{ if .Hidden }}
+	// Hidden Configuration
+	&config.Section{
+		ID: "{{ .ID }}",
+		Groups: config.GroupSlice{
+{{ range .Groups }}			&config.Group{
+				ID: "{{ .ID }}",
+				Fields: config.FieldSlice{
+{{ range .Fields }}					&config.Field{
+						// Path: ` + "`{{ .Path }}`" + `,
+						ID:      "{{ .ID }}",
+						Type:    config.TypeHidden,
+						Visible: config.VisibleNo,
+						Scope:   config.NewScopePerm(config.ScopeDefaultID),
+						Default: {{ .Default }},
+					},
+{{ end }}				},
+			},
+{{ end }}		},
+	},
+{{ else }}	&config.Section{
+		ID:        "{{ .ID }}",
+		Label:     {{ .Label | goString }},
+		SortOrder: {{ .SortOrder }},
+		Scope:     {{ .Scope }},
+		Groups: config.GroupSlice{
+{{ range .Groups }}			&config.Group{
+				ID:        "{{ .ID }}",
+				Label:     {{ .Label | goString }},
+				Comment:   {{ .Comment | goString }},
+				SortOrder: {{ .SortOrder }},
+				Scope:     {{ .Scope }},
+				Fields: config.FieldSlice{
+{{ range .Fields }}					&config.Field{
+						// Path: ` + "`{{ .Path }}`" + `,{{ if .SystemPath }}
+						// SystemPath: ` + "`{{ .SystemPath }}`" + `,{{ end }}
+						ID:           "{{ .ID }}",
+						Label:        {{ .Label | goString }},
+						Comment:      {{ .Comment | goString }},
+						Type:         {{ .Type }},{{ with .TypeComment }} // {{ . }}{{ end }}
+						SortOrder:    {{ .SortOrder }},
+						Visible:      config.VisibleYes,
+						Scope:        {{ .Scope }},
+						Default:      {{ .Default }},
+						BackendModel: {{ .BackendModel }},{{ with .BackendClass }} // {{ . }}{{ end }}
+						SourceModel:  {{ .SourceModel }},{{ with .SourceClass }} // {{ . }}{{ end }}
+					},
+{{ end }}				},
+			},
+{{ end }}		},
+	},
+{{ end }}{{ end }})
+`
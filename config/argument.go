@@ -31,6 +31,9 @@ type ArgFunc func(*arg)
 // ScopeWebsite wrapper helper function. See Scope()
 func ScopeWebsite(r ScopeIDer) ArgFunc { return Scope(ScopeWebsiteID, r) }
 
+// ScopeStoreGroup wrapper helper function for the optional group scope. See Scope()
+func ScopeStoreGroup(r ScopeIDer) ArgFunc { return Scope(ScopeGroupID, r) }
+
 // ScopeStore wrapper helper function. See Scope()
 func ScopeStore(r ScopeIDer) ArgFunc { return Scope(ScopeStoreID, r) }
 
@@ -65,6 +68,10 @@ func Path(paths ...string) ArgFunc {
 // scope not exists.
 func NoBubble() ArgFunc { return func(a *arg) { a.nb = true } }
 
+// RawValue writes the value as it is without the BeforeSave() hook of the
+// BackendModel, e.g. an already encrypted value from core_config_data.
+func RawValue() ArgFunc { return func(a *arg) { a.nh = true } }
+
 // Value sets the value for a scope key.
 func Value(v interface{}) ArgFunc { return func(a *arg) { a.v = v } }
 
@@ -91,6 +98,8 @@ type arg struct {
 	r  ScopeIDer
 	nb bool        // noBubble, if false value search: (store|website) -> default
 	v  interface{} // value use for saving
+	// nh noHooks skips BeforeSave() of the BackendModel, see RawValue().
+	nh bool
 }
 
 // this "cache" should covers ~80% of all store setups
@@ -142,10 +151,20 @@ func (a *arg) scopeID() string {
 	return "0"
 }
 
+// scopeIDInt64 returns the ID of the ScopeIDer or 0 if not set.
+func (a *arg) scopeIDInt64() int64 {
+	if a.r != nil {
+		return a.r.ScopeID()
+	}
+	return 0
+}
+
 func (a *arg) scopeRange() string {
 	switch a.s {
 	case ScopeWebsiteID:
 		return ScopeRangeWebsites
+	case ScopeGroupID:
+		return ScopeRangeGroups
 	case ScopeStoreID:
 		return ScopeRangeStores
 	}
@@ -0,0 +1,187 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/utils/cast"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// EncryptionPrefix marks an encrypted value. The remaining part is the base64
+// encoded nonce and cipher text.
+const EncryptionPrefix = "cs_enc1:"
+
+var (
+	// ErrEncryptionKeyMissing gets returned when encrypting or decrypting without a key.
+	ErrEncryptionKeyMissing = errors.New("Encryption key is missing")
+	// ErrNotEncrypted gets returned when decrypting a value without the EncryptionPrefix.
+	ErrNotEncrypted = errors.New("Value is not encrypted")
+)
+
+type (
+	// Crypter encrypts and decrypts values, see RotateEncryptionKey().
+	Crypter interface {
+		// Encrypt returns the encrypted value with the EncryptionPrefix.
+		Encrypt(plain string) (string, error)
+		// Decrypt expects a value with the EncryptionPrefix.
+		Decrypt(encrypted string) (string, error)
+	}
+
+	// BackendEncryption encrypts with AES-GCM in BeforeSave() and decrypts in
+	// AfterLoad(). The key must be set with SetKey() during the start of the app.
+	BackendEncryption struct {
+		mu   sync.RWMutex
+		aead cipher.AEAD
+	}
+)
+
+var (
+	_ FieldBackendModeller = (*BackendEncryption)(nil)
+	_ Crypter              = (*BackendEncryption)(nil)
+)
+
+// DefaultEncryption can be used as BackendModel in all package configurations.
+// Set the key at the start of the app via DefaultEncryption.SetKey().
+var DefaultEncryption = NewBackendEncryption()
+
+// NewBackendEncryption creates a new encryption backend model without a key.
+func NewBackendEncryption() *BackendEncryption {
+	return &BackendEncryption{}
+}
+
+// SetKey sets the AES key which must be 16, 24 or 32 bytes long.
+func (be *BackendEncryption) SetKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	be.mu.Lock()
+	be.aead = aead
+	be.mu.Unlock()
+	return nil
+}
+
+// BeforeSave encrypts the value. Write already encrypted values with RawValue().
+func (be *BackendEncryption) BeforeSave(_ ModelConstructor, v interface{}) (interface{}, error) {
+	s, err := cast.ToStringE(v)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	return be.Encrypt(s)
+}
+
+// AfterLoad decrypts the value. Values without the EncryptionPrefix will be
+// returned unchanged.
+func (be *BackendEncryption) AfterLoad(_ ModelConstructor, v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok || false == isEncrypted(s) {
+		return v, nil
+	}
+	return be.Decrypt(s)
+}
+
+// Encrypt encrypts plain with a random nonce.
+func (be *BackendEncryption) Encrypt(plain string) (string, error) {
+	be.mu.RLock()
+	aead := be.aead
+	be.mu.RUnlock()
+	if aead == nil {
+		return "", ErrEncryptionKeyMissing
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errgo.Mask(err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+	return EncryptionPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value created by Encrypt().
+func (be *BackendEncryption) Decrypt(encrypted string) (string, error) {
+	if false == isEncrypted(encrypted) {
+		return "", ErrNotEncrypted
+	}
+	be.mu.RLock()
+	aead := be.aead
+	be.mu.RUnlock()
+	if aead == nil {
+		return "", ErrEncryptionKeyMissing
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encrypted[len(EncryptionPrefix):])
+	if err != nil {
+		return "", errgo.Mask(err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errgo.New("Encrypted value too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errgo.Mask(err)
+	}
+	return string(plain), nil
+}
+
+// isEncrypted checks for the EncryptionPrefix.
+func isEncrypted(s string) bool {
+	return strings.HasPrefix(s, EncryptionPrefix)
+}
+
+// RotateEncryptionKey decrypts all encrypted values in core_config_data with
+// the Crypter from and encrypts them with the Crypter to. Returns the number of
+// updated rows. Use a dbr.Tx to make the rotation atomic.
+func RotateEncryptionKey(dbrSess dbr.SessionRunner, from, to Crypter) (int, error) {
+	ccd, err := loadCoreConfigData(dbrSess)
+	if err != nil {
+		return 0, errgo.Mask(err)
+	}
+	tn := TableCollection.Name(TableIndexCoreConfigData)
+	rotated := 0
+	for _, cd := range ccd {
+		if cd == nil || false == cd.Value.Valid || false == isEncrypted(cd.Value.String) {
+			continue
+		}
+		plain, err := from.Decrypt(cd.Value.String)
+		if err != nil {
+			return rotated, errgo.Newf("Cannot decrypt config_id %d, path %s: %s", cd.ConfigID, cd.Path, err)
+		}
+		enc, err := to.Encrypt(plain)
+		if err != nil {
+			return rotated, errgo.Mask(err)
+		}
+		if _, err := dbrSess.Update(tn).Set("value", enc).Where("config_id = ?", cd.ConfigID).Exec(); err != nil {
+			return rotated, errgo.Mask(err)
+		}
+		rotated++
+	}
+	if log.IsDebug() {
+		log.Debug("config.RotateEncryptionKey", "rows", len(ccd), "rotated", rotated)
+	}
+	return rotated, nil
+}
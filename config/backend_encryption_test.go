@@ -0,0 +1,103 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackendEncryption(t *testing.T) {
+	be := config.NewBackendEncryption()
+	_, err := be.Encrypt("secret")
+	assert.EqualError(t, err, config.ErrEncryptionKeyMissing.Error())
+	assert.Error(t, be.SetKey([]byte("short")))
+	assert.NoError(t, be.SetKey([]byte("0123456789abcdef0123456789abcdef")))
+
+	enc, err := be.Encrypt("secret")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, config.EncryptionPrefix))
+	assert.NotContains(t, enc, "secret")
+
+	plain, err := be.Decrypt(enc)
+	assert.NoError(t, err)
+	assert.Exactly(t, "secret", plain)
+
+	_, err = be.Decrypt("secret")
+	assert.EqualError(t, err, config.ErrNotEncrypted.Error())
+
+	other := config.NewBackendEncryption()
+	assert.NoError(t, other.SetKey([]byte("fedcba9876543210")))
+	_, err = other.Decrypt(enc)
+	assert.Error(t, err)
+}
+
+func TestManagerEncryptObscureFields(t *testing.T) {
+	be := config.NewBackendEncryption()
+	assert.NoError(t, be.SetKey([]byte("0123456789abcdef")))
+
+	storage := config.NewMemoryStorage()
+	m := config.NewManager(config.SetManagerStorage(storage))
+	m.ApplyDefaults(config.NewConfiguration(
+		&config.Section{
+			ID: "payment",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "paypal",
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `payment/paypal/api_password`,
+							ID:           "api_password",
+							Type:         config.TypeObscure,
+							BackendModel: be,
+						},
+						&config.Field{
+							// Path: `payment/paypal/api_username`,
+							ID:   "api_username",
+							Type: config.TypeText,
+						},
+					},
+				},
+			},
+		},
+	))
+
+	assert.NoError(t, m.Write(config.Path("payment/paypal/api_password"), config.Value("s3cr3t"), config.ScopeWebsite(config.ScopeID(1))))
+	assert.NoError(t, m.Write(config.Path("payment/paypal/api_username"), config.Value("gopher")))
+
+	raw, err := storage.Get("websites/1/payment/paypal/api_password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(raw.(string), config.EncryptionPrefix))
+	assert.Exactly(t, "s3cr3t", m.GetString(config.Path("payment/paypal/api_password"), config.ScopeWebsite(config.ScopeID(1))))
+	assert.Exactly(t, "s3cr3t", m.GetString(config.Path("payment/paypal/api_password")))
+
+	// already encrypted values, e.g. from core_config_data, must be written raw
+	assert.NoError(t, m.Write(config.Path("payment/paypal/api_password"), config.Value(raw), config.ScopeStore(config.ScopeID(2)), config.NoBubble(), config.RawValue()))
+	assert.Exactly(t, "s3cr3t", m.GetString(config.Path("payment/paypal/api_password"), config.ScopeStore(config.ScopeID(2))))
+	stored, err := storage.Get("stores/2/payment/paypal/api_password")
+	assert.NoError(t, err)
+	assert.Exactly(t, raw, stored)
+
+	// without RawValue() the value will be encrypted like any other value
+	assert.NoError(t, m.Write(config.Path("payment/paypal/api_password"), config.Value(raw), config.ScopeStore(config.ScopeID(3)), config.NoBubble()))
+	assert.Exactly(t, raw, m.GetString(config.Path("payment/paypal/api_password"), config.ScopeStore(config.ScopeID(3))))
+
+	raw, err = storage.Get("default/0/payment/paypal/api_username")
+	assert.NoError(t, err)
+	assert.Exactly(t, "gopher", raw)
+}
@@ -43,7 +43,7 @@ var bsstj string
 func BenchmarkSectionSliceToJson(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		if bsstj = packageAllConfiguration.ToJson(); bsstj == "" {
+		if bsstj = packageAllConfiguration.ToJSON(); bsstj == "" {
 			b.Error("JSON is empty!")
 		}
 	}
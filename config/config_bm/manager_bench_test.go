@@ -0,0 +1,140 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/utils/log"
+)
+
+// storeWebsite is a store ScopeIDer which knows its website
+type storeWebsite struct {
+	storeID, websiteID int64
+}
+
+func (sw storeWebsite) ScopeID() int64   { return sw.storeID }
+func (sw storeWebsite) WebsiteID() int64 { return sw.websiteID }
+
+const (
+	// benchmarkPath has been set in the store scope
+	benchmarkPath = "web/cookie/cookie_path"
+	// benchmarkPathDefault bubbles into the default scope
+	benchmarkPathDefault = "web/default/cms_no_cookies"
+)
+
+var benchmarkGetString string
+
+func newBenchmarkManager(b *testing.B) *config.Manager {
+	// the debug logging of utils/cast would dominate the results
+	log.SetLevel(log.StdLevelInfo)
+	m := config.NewManager()
+	m.ApplyDefaults(packageAllConfiguration)
+	if err := m.Write(config.Path(benchmarkPath), config.Value("/de"), config.ScopeStore(config.ScopeID(2)), config.NoBubble()); err != nil {
+		b.Fatal(err)
+	}
+	return m
+}
+
+// BenchmarkManagerGetStringStore	 1998152	       597 ns/op	     160 B/op	       3 allocs/op
+func BenchmarkManagerGetStringStore(b *testing.B) {
+	m := newBenchmarkManager(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkGetString = m.GetString(config.Path(benchmarkPath), config.ScopeStore(config.ScopeID(2)))
+	}
+}
+
+// BenchmarkManagerGetStringBubble	 1454760	       792 ns/op	     224 B/op	       4 allocs/op
+func BenchmarkManagerGetStringBubble(b *testing.B) {
+	m := newBenchmarkManager(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkGetString = m.GetString(config.Path(benchmarkPathDefault), config.ScopeStore(config.ScopeID(2)))
+	}
+}
+
+// BenchmarkManagerSnapshotGetString	12742371	       114 ns/op	       0 B/op	       0 allocs/op
+func BenchmarkManagerSnapshotGetString(b *testing.B) {
+	m := newBenchmarkManager(b)
+	sw := storeWebsite{2, 1}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkGetString = m.Snapshot(config.ScopeStoreID, sw).GetString(benchmarkPath)
+	}
+	if benchmarkGetString != "/de" {
+		b.Fatalf("Unexpected value %q", benchmarkGetString)
+	}
+}
+
+// BenchmarkManagerSnapshotGetStringBubble	10841240	       114 ns/op	       0 B/op	       0 allocs/op
+func BenchmarkManagerSnapshotGetStringBubble(b *testing.B) {
+	m := newBenchmarkManager(b)
+	sw := storeWebsite{2, 1}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkGetString = m.Snapshot(config.ScopeStoreID, sw).GetString(benchmarkPathDefault)
+	}
+	if benchmarkGetString != "enable-cookies" {
+		b.Fatalf("Unexpected value %q", benchmarkGetString)
+	}
+}
+
+// BenchmarkManagerSnapshotGetStringParallel	 8249395	       172 ns/op	      16 B/op	       1 allocs/op
+func BenchmarkManagerSnapshotGetStringParallel(b *testing.B) {
+	m := newBenchmarkManager(b)
+	sw := storeWebsite{2, 1}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var s string
+		for pb.Next() {
+			s = m.Snapshot(config.ScopeStoreID, sw).GetString(benchmarkPath)
+		}
+		_ = s
+	})
+}
+
+// BenchmarkManagerGetStringStoreParallel	 1409341	       743 ns/op	     160 B/op	       3 allocs/op
+func BenchmarkManagerGetStringStoreParallel(b *testing.B) {
+	m := newBenchmarkManager(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var s string
+		for pb.Next() {
+			s = m.GetString(config.Path(benchmarkPath), config.ScopeStore(config.ScopeID(2)))
+		}
+		_ = s
+	})
+}
+
+// BenchmarkManagerSnapshotBuild measures the rebuild after a Write.
+// BenchmarkManagerSnapshotBuild	    1082	   1312536 ns/op	  294525 B/op	    2556 allocs/op
+func BenchmarkManagerSnapshotBuild(b *testing.B) {
+	m := newBenchmarkManager(b)
+	sw := storeWebsite{2, 1}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.InvalidateSnapshots()
+		benchmarkGetString = m.Snapshot(config.ScopeStoreID, sw).GetString(benchmarkPath)
+	}
+}
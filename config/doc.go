@@ -94,5 +94,192 @@ Store Scope:
 
 An io.Reader is provided with automatic Close() calling.
 
+Persisting Writes
+
+The Manager holds all values only in memory. To store a value permanently in the
+table core_config_data use the DBWriter. It upserts the value and forwards afterwards
+the same arguments to the Manager:
+
+	dw := config.NewDBWriter(dbrSess, config.SetDBWriterSections(allPackageConfigs), config.SetDBWriterManager(config.DefaultManager))
+	err := dw.Write(config.Path("currency", "option", "base"), config.Value("EUR"), config.ScopeWebsite(w))
+
+If the Field of a path does not allow the scope, DBWriter returns ErrScopeNotAllowed.
+Only the scope of the arguments will be written. To write website and store values
+also into the default scope enable config.SetDBWriterBubble(true). A nil value
+deletes the row. Within a transaction the Manager receives the values after the
+commit of the DBWriter, a rollback leaves the Manager untouched:
+
+	tx, err := dbrSess.Begin()
+	dw := config.NewDBWriter(tx, config.SetDBWriterManager(config.DefaultManager))
+	if err := dw.Write(config.Path("currency", "option", "base"), config.Value("EUR")); err != nil {
+		return dw.Rollback()
+	}
+	err = dw.Commit()
+
+Storage
+
+The Manager stores all values in a Storager, default is an in-memory storage.
+Backends can be layered whereas the first layer has the highest precedence.
+Read only layers, like the file storage, will be skipped on writes. Writing a
+key which a read only layer already contains returns ErrKeyOverridden. The
+defaults of ApplyDefaults() have always the lowest precedence:
+
+	file, err := config.NewFileStorage("config.yaml")
+	db := config.NewDBStorage(dbrSess)
+	err = db.Load()
+	m := config.NewManager(config.SetManagerStorage(config.NewLayeredStorage(file, db)))
+
+Environment variables override all other values, including the ones from
+ApplyCoreConfigData(). Write() returns ErrKeyOverridden for such keys. The
+mapping is described in EnvStorage. Variables which cannot be mapped will be
+logged and skipped unless config.SetEnvStrict(true) has been set:
+
+	es, err := config.NewEnvStorage(os.Environ(), config.SetEnvScopeCodeResolver(myResolver))
+	m := config.NewManager(config.SetManagerEnvOverrides(es))
+	log.Debug("overrides", m.DebugOverrides())
+
+Export and Import
+
+Export() copies all values of the Storager into a ScopedValues document which
+can be written as sorted JSON or YAML. Import() applies such a document via
+Write(). The values will be compared with the stored values of each scope and
+checked before the first write. Keys overridden by the environment will be
+reported and skipped. With dryRun set to true only the changes will be returned:
+
+	sv, err := config.ReadScopedValuesYAML(f)
+	changes, err := config.DefaultManager.Import(sv, true)
+	fmt.Print(changes)
+
+Placeholders
+
+A Resolver expands placeholders like {{web/unsecure/base_url}} with the value
+of the referenced path in the same scope. Cycles return ErrPlaceholderCycle.
+Custom placeholders can be registered:
+
+	rs := config.NewResolver(config.DefaultManager).Register("base_url", myProvider)
+	url, err := rs.String(config.Path("web/secure/base_url"), config.ScopeStore(s))
+
+Subscriptions
+
+A MessageReceiver gets notified after a Write() whose path starts with the
+subscribed path prefix. ScopeAbsentID as ScopeGroup receives the changes of all
+scopes, a ScopeIDer only the changes of one website, group or store:
+
+	id, err := config.DefaultManager.Subscribe("web/secure", config.ScopeAbsentID, nil, myReceiver)
+	id, err := config.DefaultManager.Subscribe("web/secure", config.ScopeStoreID, config.ScopeID(2), myReceiver)
+
+The Reloader polls the table core_config_data and writes only the changed
+values into the Manager, which then notifies the subscribers:
+
+	r := config.NewReloader(config.DefaultManager, dbrSess, time.Minute)
+	r.Start()
+	defer r.Stop()
+
+Backend Models
+
+The BackendModel of a Field gets called by the Manager and the DBWriter. BeforeSave()
+validates or transforms a value before it will be stored and its error gets
+returned by Write(). AfterLoad() transforms the value before a getter returns it.
+Values written with RawValue() skip BeforeSave(), e.g. values from core_config_data
+which have already been saved. The DBWriter passes the Reader of SetDBWriterReader()
+or of SetDBWriterManager() to the BackendModel and returns ErrReaderMissing without one.
+
+Encryption
+
+BackendEncryption as BackendModel of a Field encrypts the value on Write() and
+decrypts it on read. The key must be set during the start of the app:
+
+	err := config.DefaultEncryption.SetKey(key) // 16, 24 or 32 bytes
+	...
+	&config.Field{ID: "api_password", Type: config.TypeObscure, BackendModel: config.DefaultEncryption}
+
+The command cs keyrotate re-encrypts all values in core_config_data
+with a new key.
+
+HTML Forms
+
+HTMLForm renders a SectionSlice as an admin form for one scope. Fields whose
+ScopePerm excludes the scope will be skipped. In the website and store scope each
+Field has a checkbox to inherit the parent value. The input elements will be
+generated by FieldTyper.ToHTML():
+
+	hf := config.NewHTMLForm(config.DefaultManager, config.ScopeWebsiteID, w, config.SetHTMLFormAction("/admin/config"))
+	err := hf.Render(resp, ss)
+
+Snapshots
+
+A Snapshot contains all values of one scope with the fallback to the website and
+default scope already resolved. Reads are lock-free map lookups without building
+a scope path. The Manager caches the Snapshots and a Write replaces only the
+cached Snapshots whose value of the written path changes:
+
+	s := config.DefaultManager.Snapshot(config.ScopeStoreID, store)
+	url := s.GetString("web/unsecure/base_url")
+
+Scope fallback
+
+A value of a store or group scope falls back to its website and then to the
+default scope. The website of a store or group (config.ScopeStoreGroup()) will
+be taken from a ScopeIDer which implements WebsiteIDer, e.g. store.Store, or
+from the ScopeHierarchy of the Manager:
+
+	config.DefaultManager.SetScopeHierarchy(storeManager)
+	config.DefaultManager.GetString(config.Path("web/cookie/cookie_path"), config.ScopeStore(config.ScopeID(2)))
+	// lookup: stores/2/web/cookie/cookie_path -> websites/1/web/cookie/cookie_path -> default/0/web/cookie/cookie_path
+
+Without a website only the default scope will be used. Group level values are
+optional and stored with the scope path groups/<id>/<path>. The Manager caches
+the website IDs of the ScopeHierarchy until InvalidateScopeHierarchy() gets
+called, which the store.Manager does after creating, updating or deleting a
+website, group or store.
+
+History
+
+HistoryWriter wraps the DBWriter and records each write with the old and new
+value, the actor and the time in the table TableHistory. Create the table with
+TableHistorySchema. The HistoryWriter requires a dbr.Tx which the wrapped
+DBWriter must also use. Writes can be grouped into a changeset and rolled back.
+Restoring a path which was not set deletes its row:
+
+	dw := config.NewDBWriter(tx, config.SetDBWriterManager(config.DefaultManager))
+	hw := config.NewHistoryWriter(tx, dw)
+	cs := hw.NewChangeset("admin")
+	err := cs.Write(config.Path("web/secure/base_url"), config.Value("https://shop.io/"), config.ScopeWebsite(w))
+	err = dw.Commit()
+	entries, err := hw.History("web/secure/base_url")
+	_, err = hw.RollbackPath(entries[1].HistoryID, "admin")
+	_, err = hw.RollbackChangeset(cs.Changeset(), "admin")
+
+Testing
+
+MockReplayReader returns preloaded values and records all requested paths.
+Unexpected paths fail the test:
+
+	mr := config.NewMockReplayReader(t)
+	err := mr.PreloadJSON(fixture) // {"stores/1/web/unsecure/base_url": "http://de.io/"}
+	// run the code under test with mr as config.Reader
+	assert.Exactly(t, []string{"stores/1/web/unsecure/base_url"}, mr.Requests())
+	assert.Empty(t, mr.Unused())
+
+HTTP API
+
+HTTPHandler serves the SectionSlice and the effective values per scope as JSON
+and writes values posted as a JSON object through a Writer. Section.Permission
+and the ACLFunc of the request must both grant PermRead or PermWrite. Without an
+ACLFunc the handler is read only:
+
+	h := config.NewHTTPHandler(ss, config.DefaultManager, dbWriter, config.SetHTTPHandlerACL(aclFn))
+	http.Handle("/admin/config/", http.StripPrefix("/admin/config", h))
+	// GET  /admin/config/sections
+	// GET  /admin/config/values?scope=stores&scope_id=2
+	// POST /admin/config/values?scope=websites&scope_id=1 {"web/cookie/cookie_path":"/de"}
+
+Values of TypeObscure fields will be returned as ObscureMask and a posted
+ObscureMask keeps the stored secret. The ScopePerm of the Section, Group and
+Field must contain the scope. All paths and values of a POST will be checked
+before the first write, like the strict mode of the Manager and the BeforeSave()
+hook would do. The response lists the written paths, also if a later write fails
+in the storage. Use a DBWriter with a dbr.Tx to roll back.
+
 */
 package config
@@ -15,6 +15,7 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"sort"
 	"strings"
@@ -47,7 +48,7 @@ type (
 	// FieldTyper defines which front end type a configuration value is and generates the HTML for it
 	FieldTyper interface {
 		Type() FieldType
-		ToHTML() []byte // @see \Magento\Framework\Data\Form\Element\AbstractElement
+		ToHTML(FieldHTML) []byte // @see \Magento\Framework\Data\Form\Element\AbstractElement
 	}
 
 	// FieldSlice contains a set of Fields
@@ -83,11 +84,6 @@ func (i FieldType) Type() FieldType {
 	return i
 }
 
-// ToHTML noop function to satisfies the interface of Field.Type
-func (i FieldType) ToHTML() []byte {
-	return nil
-}
-
 // FindByID returns a Field pointer or nil if not found
 func (fs FieldSlice) FindByID(id string) (*Field, error) {
 	for _, f := range fs {
@@ -191,7 +187,58 @@ func (i FieldType) String() string {
 	return fieldTypeName[lo:hi]
 }
 
-// MarshalJSON implements marshaling into a human readable string. @todo UnMarshal
+// MarshalJSON implements marshaling into a human readable string.
 func (i FieldType) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + strings.ToLower(i.String()[4:]) + `"`), nil
 }
+
+// UnmarshalJSON implements unmarshaling of the human readable string or null.
+func (i *FieldType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errgo.Mask(err)
+	}
+	*i = 0
+	if name == "" {
+		return nil
+	}
+	for ft := TypeButton; ft <= TypeTime; ft++ {
+		if strings.ToLower(ft.String()[4:]) == name {
+			*i = ft
+			return nil
+		}
+	}
+	return errgo.Newf("Unknown FieldType %q", name)
+}
+
+// UnmarshalJSON implements unmarshaling of a Field. The SourceModel and the
+// BackendModel cannot be restored and are always nil. A numeric Default
+// value becomes a float64.
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var jf struct {
+		ID        string
+		Type      FieldType
+		Label     string
+		Comment   string
+		Scope     ScopePerm
+		SortOrder int
+		Visible   Visible
+		Default   interface{}
+	}
+	if err := json.Unmarshal(data, &jf); err != nil {
+		return errgo.Mask(err)
+	}
+	*f = Field{
+		ID:        jf.ID,
+		Label:     jf.Label,
+		Comment:   jf.Comment,
+		Scope:     jf.Scope,
+		SortOrder: jf.SortOrder,
+		Visible:   jf.Visible,
+		Default:   jf.Default,
+	}
+	if jf.Type > 0 {
+		f.Type = jf.Type
+	}
+	return nil
+}
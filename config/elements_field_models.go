@@ -40,34 +40,102 @@ type (
 
 	// ModelConstructor implements different fields/functions which can be differently used
 	// by the FieldSourceModeller or FieldBackendModeller types.
-	// Nearly all functions will return not nil. The Construct() function takes what it needs.
+	// Nearly all functions will return not nil. ScopedOptions() and the hooks take what they need.
 	ModelConstructor struct {
 		// Scope contains a website/store ID or nil (=default scope)
 		Scope ScopeIDer
+		// ScopeGroup defines if Scope is a website or a store ID.
+		ScopeGroup ScopeGroup
+		// Path contains the path of the value in ScopedOptions(), BeforeSave() and AfterLoad().
+		Path string
 		// ConfigReader returns the configuration reader and never nil
 		ConfigReader Reader
 		// @todo more fields to be added, depends on the overall requirements of all Magento models.
 	}
 
 	// FieldSourceModeller defines how to retrieve all option values. Mostly used for frontend output.
-	// The Construct() must be used because NOT all fields of ModelConstructor are available during
-	// init process and can of course change during the running app. Also to prevent circular dependencies.
+	// A source model must be stateless because one instance of a package configuration
+	// will be shared by all scopes and goroutines.
 	FieldSourceModeller interface {
-		Construct(ModelConstructor) error
 		Options() ValueLabelSlice
 	}
 
-	// FieldBackendModeller defines how to save and load the data @todo rethink AddData
-	// In Magento slang: beforeSave() and afterLoad().
-	// The Construct() must be used because NOT all fields of ModelConstructor are available during
-	// init process and can of course change during the running app. Also to prevent circular dependencies.
+	// FieldSourceScopedModeller is an optional interface of a FieldSourceModeller
+	// whose options depend on the scope, e.g. the installed currencies. The
+	// ModelConstructor contains the scope and the Reader of the current call.
+	// ScopedOptions() will be preferred over Options().
+	FieldSourceScopedModeller interface {
+		FieldSourceModeller
+		ScopedOptions(ModelConstructor) ValueLabelSlice
+	}
+
+	// FieldBackendModeller defines how to save and load the data.
+	// In Magento slang: beforeSave() and afterLoad(). Both hooks receive the
+	// ModelConstructor of the current call because NOT all of its fields are
+	// available during the init process and they can change during the running app.
 	FieldBackendModeller interface {
-		Construct(ModelConstructor) error
-		AddData(interface{})
-		Save() error
+		// BeforeSave gets called in Write() before the value will be stored. It can
+		// validate or transform the value. An error aborts the Write(). Not
+		// called for nil values and values written with RawValue().
+		BeforeSave(mc ModelConstructor, v interface{}) (interface{}, error)
+		// AfterLoad gets called with the raw value before the Manager returns it.
+		AfterLoad(mc ModelConstructor, v interface{}) (interface{}, error)
 	}
 )
 
+// newModelConstructor creates the ModelConstructor for the hooks of a FieldBackendModeller.
+func newModelConstructor(r Reader, a *arg) ModelConstructor {
+	sg := a.s
+	if a.isDefault() {
+		sg = ScopeDefaultID
+	}
+	return ModelConstructor{
+		Scope:        a.r,
+		ScopeGroup:   sg,
+		Path:         a.p,
+		ConfigReader: r,
+	}
+}
+
+// sourceOptions returns the ScopedOptions() of a FieldSourceScopedModeller or
+// the Options() of sm.
+func sourceOptions(sm FieldSourceModeller, mc ModelConstructor) ValueLabelSlice {
+	if ssm, ok := sm.(FieldSourceScopedModeller); ok {
+		return ssm.ScopedOptions(mc)
+	}
+	return sm.Options()
+}
+
+// backendModel returns the BackendModel of the Field of the path or nil.
+func backendModel(ss SectionSlice, path string) FieldBackendModeller {
+	f, err := ss.FindFieldByPath(path)
+	if err != nil {
+		return nil
+	}
+	return f.BackendModel
+}
+
+// beforeSave runs the BeforeSave hook of the BackendModel and returns the new value.
+func beforeSave(ss SectionSlice, r Reader, a *arg) (interface{}, error) {
+	if a.v == nil || a.nh {
+		return a.v, nil
+	}
+	bm := backendModel(ss, a.p)
+	if bm == nil {
+		return a.v, nil
+	}
+	return bm.BeforeSave(newModelConstructor(r, a), a.v)
+}
+
+// afterLoad runs the AfterLoad hook of the BackendModel and returns the new value.
+func afterLoad(ss SectionSlice, r Reader, a *arg, v interface{}) (interface{}, error) {
+	bm := backendModel(ss, a.p)
+	if bm == nil {
+		return v, nil
+	}
+	return bm.AfterLoad(newModelConstructor(r, a), v)
+}
+
 // SortByLabel sorts by label in asc or desc direction
 func (s ValueLabelSlice) SortByLabel(d utils.SortDirection) ValueLabelSlice {
 	var si sort.Interface
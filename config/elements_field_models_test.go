@@ -15,6 +15,8 @@
 package config_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/corestoreio/csfw/config"
@@ -51,3 +53,61 @@ func TestValueLabelSlice(t *testing.T) {
 		assert.Exactly(t, test.wantLabel, test.have.ToJSON(), "SortByLabel Index %d", i)
 	}
 }
+
+type upperBackend struct {
+	mc config.ModelConstructor
+}
+
+func (ub *upperBackend) BeforeSave(mc config.ModelConstructor, v interface{}) (interface{}, error) {
+	ub.mc = mc
+	s := v.(string)
+	if s == "" {
+		return nil, errors.New("Empty value")
+	}
+	return strings.ToUpper(s), nil
+}
+
+func (ub *upperBackend) AfterLoad(_ config.ModelConstructor, v interface{}) (interface{}, error) {
+	return "<" + v.(string) + ">", nil
+}
+
+func TestFieldBackendModellerHooks(t *testing.T) {
+	ub := &upperBackend{}
+	m := config.NewManager()
+	m.ApplyDefaults(config.NewConfiguration(
+		&config.Section{
+			ID: "web",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "cookie",
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `web/cookie/name`,
+							ID:           "name",
+							Type:         config.TypeText,
+							BackendModel: ub,
+						},
+					},
+				},
+			},
+		},
+	))
+
+	assert.EqualError(t, m.Write(config.Path("web/cookie/name"), config.Value(""), config.ScopeStore(config.ScopeID(3))), "Empty value")
+	_, _, err := m.Lookup(config.Path("web/cookie/name"), config.ScopeStore(config.ScopeID(3)))
+	assert.EqualError(t, err, config.ErrKeyNotFound.Error())
+
+	assert.NoError(t, m.Write(config.Path("web/cookie/name"), config.Value("cs"), config.ScopeStore(config.ScopeID(3))))
+	assert.Exactly(t, config.ScopeStoreID, ub.mc.ScopeGroup)
+	assert.Exactly(t, int64(3), ub.mc.Scope.ScopeID())
+	assert.Exactly(t, "web/cookie/name", ub.mc.Path)
+	assert.Exactly(t, m, ub.mc.ConfigReader)
+
+	assert.Exactly(t, "<CS>", m.GetString(config.Path("web/cookie/name"), config.ScopeStore(config.ScopeID(3))))
+	assert.Exactly(t, "<CS>", m.GetString(config.Path("web/cookie/name")))
+}
+
+func TestSourceYesNoEnableDisable(t *testing.T) {
+	assert.Exactly(t, `[{"Value":"1","Label":"Yes"},{"Value":"0","Label":"No"}]`+"\n", config.NewSourceYesNo().Options().ToJSON())
+	assert.Exactly(t, `[{"Value":"1","Label":"Enable"},{"Value":"0","Label":"Disable"}]`+"\n", config.NewSourceEnableDisable().Options().ToJSON())
+}
@@ -46,7 +46,8 @@ type (
 		// Scope: bit value eg: showInDefault="1" showInWebsite="1" showInStore="1"
 		Scope     ScopePerm `json:",omitempty"`
 		SortOrder int       `json:",omitempty"`
-		// Permission some kind of ACL if someone is allowed for no,read or write access @todo
+		// Permission read/write ACL of the HTTPHandler, a bit set of PermRead and
+		// PermWrite. 0 does not restrict the access.
 		Permission uint `json:",omitempty"`
 		Groups     GroupSlice
 	}
@@ -219,6 +220,22 @@ func (ss SectionSlice) ToJSON() string {
 	return buf.String()
 }
 
+// UnmarshalJSON decodes the output of ToJSON() and validates the sections.
+// The SourceModel and the BackendModel of a Field are always nil.
+func (ss *SectionSlice) UnmarshalJSON(data []byte) error {
+	var sections []*Section
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return errgo.Mask(err)
+	}
+	if len(sections) > 0 {
+		if err := SectionSlice(sections).Validate(); err != nil {
+			return errgo.Mask(err)
+		}
+	}
+	*ss = sections
+	return nil
+}
+
 // Validate checks for duplicated configuration paths in all three hierarchy levels.
 func (ss SectionSlice) Validate() error {
 	if len(ss) == 0 {
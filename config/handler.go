@@ -0,0 +1,330 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/utils/log"
+)
+
+// Permissions of a Section and of a request to the HTTPHandler.
+const (
+	PermRead uint = 1 << iota
+	PermWrite
+)
+
+var (
+	// ErrPermissionDenied gets returned when the ACL does not allow the access.
+	ErrPermissionDenied = errors.New("Permission denied")
+	// ErrInvalidScope gets returned when the scope or scope_id query parameter
+	// cannot be parsed.
+	ErrInvalidScope = errors.New("Invalid scope or scope_id")
+)
+
+type (
+	// ACLFunc returns the permissions of a request, a bit set of PermRead and PermWrite.
+	ACLFunc func(r *http.Request) uint
+
+	// HTTPHandler provides a JSON API for the admin backend. Mount it with
+	// http.StripPrefix. Routes:
+	//	GET  /sections                              the SectionSlice, see ToJSON()
+	//	GET  /values?scope=websites&scope_id=1      effective values of a scope
+	//	POST /values?scope=websites&scope_id=1      writes {"path":value,...}
+	// The Permission of a Section and the ACLFunc must both grant the access.
+	// Paths whose ScopePerm of the Section, Group or Field excludes the scope
+	// will neither be listed nor written. All values of a POST will be checked
+	// before the first write. A write can then only fail in the storage, e.g.
+	// the database, which only a DBWriter with a dbr.Tx rolls back, therefore
+	// the response contains the written paths.
+	HTTPHandler struct {
+		ss  SectionSlice
+		r   Reader
+		w   Writer
+		acl ACLFunc
+	}
+
+	// HTTPHandlerOption option func for NewHTTPHandler()
+	HTTPHandlerOption func(*HTTPHandler)
+
+	// HTTPValue is the effective value of a path in the requested scope.
+	HTTPValue struct {
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+		// Scope from where the value has been retrieved: default, websites or stores.
+		Scope string `json:"scope"`
+	}
+
+	// HTTPWriteResult is the response of a POST. Written contains the paths
+	// in the order of the writes, also if a later write has failed in the
+	// storage.
+	HTTPWriteResult struct {
+		Written []string `json:"written"`
+		// Error and Path are set if the write of Path has failed.
+		Error string `json:"error,omitempty"`
+		Path  string `json:"path,omitempty"`
+	}
+)
+
+var _ http.Handler = (*HTTPHandler)(nil)
+
+// SetHTTPHandlerACL sets the function which returns the permissions of a request.
+// Default: only PermRead. Writing requires an ACLFunc.
+func SetHTTPHandlerACL(fn ACLFunc) HTTPHandlerOption {
+	return func(h *HTTPHandler) {
+		if fn != nil {
+			h.acl = fn
+		}
+	}
+}
+
+// NewHTTPHandler creates a new handler for the sections. Values will be read
+// from r and written to w, mostly a Manager or a DBWriter.
+func NewHTTPHandler(ss SectionSlice, r Reader, w Writer, opts ...HTTPHandlerOption) *HTTPHandler {
+	h := &HTTPHandler{
+		ss: ss,
+		r:  r,
+		w:  w,
+		acl: func(_ *http.Request) uint {
+			return PermRead
+		},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/sections" && r.Method == "GET":
+		h.getSections(w, r)
+	case r.URL.Path == "/values" && r.Method == "GET":
+		h.getValues(w, r)
+	case r.URL.Path == "/values" && r.Method == "POST":
+		h.postValues(w, r)
+	case r.URL.Path == "/sections" || r.URL.Path == "/values":
+		writeJSONError(w, http.StatusMethodNotAllowed, errors.New(http.StatusText(http.StatusMethodNotAllowed)))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// allowed checks the permission of the Section and of the request.
+func (h *HTTPHandler) allowed(s *Section, r *http.Request, perm uint) bool {
+	if s.Permission > 0 && s.Permission&perm == 0 {
+		return false
+	}
+	return h.acl(r)&perm != 0
+}
+
+// getSections writes all readable sections.
+func (h *HTTPHandler) getSections(w http.ResponseWriter, r *http.Request) {
+	ss := make(SectionSlice, 0, len(h.ss))
+	for _, s := range h.ss {
+		if s != nil && h.allowed(s, r, PermRead) {
+			ss = append(ss, s)
+		}
+	}
+	writeJSON(w, http.StatusOK, ss)
+}
+
+// scopeAllowed checks the ScopePerm of the Section, Group and Field of a path
+// like HTMLForm.visible.
+func (h *HTTPHandler) scopeAllowed(s *Section, g *Group, f *Field, a *arg) bool {
+	return permAllowed(s.Scope, a) && permAllowed(g.Scope, a) && permAllowed(f.Scope, a)
+}
+
+// checkValue checks a value in the same way as the Writer before anything
+// gets written: the strict mode and the overrides of the Manager, also behind
+// a DBWriter, and the BeforeSave() hook of the BackendModel.
+func (h *HTTPHandler) checkValue(a *arg) error {
+	var m *Manager
+	switch w := h.w.(type) {
+	case *Manager:
+		m = w
+	case *DBWriter:
+		m, _ = w.w.(*Manager)
+	}
+	if m != nil {
+		if err := m.checkWrite(a); err != nil {
+			return err
+		}
+	}
+	_, err := beforeSave(h.ss, h.r, a)
+	return err
+}
+
+// getValues writes the effective values of all readable fields in the scope.
+// Values of TypeObscure fields will be masked.
+func (h *HTTPHandler) getValues(w http.ResponseWriter, r *http.Request) {
+	scope, err := scopeFromRequest(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	sa := newArg(scope)
+
+	vals := []HTTPValue{}
+	for _, s := range h.ss {
+		if s == nil || false == h.allowed(s, r, PermRead) {
+			continue
+		}
+		for _, g := range s.Groups {
+			if g == nil {
+				continue
+			}
+			for _, f := range g.Fields {
+				if f == nil || false == h.scopeAllowed(s, g, f, sa) {
+					continue
+				}
+				p := s.ID + PS + g.ID + PS + f.ID
+				v, sg, err := h.r.Lookup(Path(p), scope)
+				switch {
+				case err == ErrKeyNotFound:
+					continue
+				case err != nil:
+					log.Error("HTTPHandler=getValues.Lookup", "err", err, "path", p)
+					continue
+				}
+				if f.Type != nil && f.Type.Type() == TypeObscure && v != nil {
+					v = ObscureMask
+				}
+				vals = append(vals, HTTPValue{Path: p, Value: v, Scope: newArg(Scope(sg, sa.r)).scopeRange()})
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, vals)
+}
+
+// postValues writes all values of the request body. All paths and values will
+// be checked before the first value gets written. TypeObscure fields whose
+// value equals ObscureMask will be skipped because the client has sent back
+// the masked value.
+func (h *HTTPHandler) postValues(w http.ResponseWriter, r *http.Request) {
+	scope, err := scopeFromRequest(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	sa := newArg(scope)
+
+	var vals map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&vals); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	paths := make([]string, 0, len(vals))
+	for p := range vals {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	written := make([]string, 0, len(paths))
+	for _, p := range paths {
+		f, err := h.ss.FindFieldByPath(p)
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, ErrFieldNotFound, p)
+			return
+		}
+		s, _ := h.ss.FindByID(p[:strings.Index(p, PS)])
+		g, _ := h.ss.FindGroupByPath(p)
+		if false == h.allowed(s, r, PermWrite) {
+			writeJSONError(w, http.StatusForbidden, ErrPermissionDenied, p)
+			return
+		}
+		if false == h.scopeAllowed(s, g, f, sa) {
+			writeJSONError(w, http.StatusForbidden, ErrScopeNotAllowed, p)
+			return
+		}
+		if s, ok := vals[p].(string); ok && s == ObscureMask && f.Type != nil && f.Type.Type() == TypeObscure {
+			delete(vals, p)
+		}
+	}
+
+	for _, p := range paths {
+		v, ok := vals[p]
+		if !ok {
+			continue
+		}
+		if err := h.checkValue(newArg(scope, Path(p), Value(v), NoBubble())); err != nil {
+			if log.IsDebug() {
+				log.Debug("HTTPHandler=postValues.checkValue", "err", err, "path", p, "val", v)
+			}
+			writeJSONError(w, http.StatusBadRequest, err, p)
+			return
+		}
+	}
+
+	for _, p := range paths {
+		v, ok := vals[p]
+		if !ok {
+			continue
+		}
+		if err := h.w.Write(Path(p), scope, Value(v), NoBubble()); err != nil {
+			if log.IsDebug() {
+				log.Debug("HTTPHandler=postValues.Write", "err", err, "path", p, "written", written)
+			}
+			writeJSON(w, http.StatusBadRequest, HTTPWriteResult{Written: written, Error: err.Error(), Path: p})
+			return
+		}
+		written = append(written, p)
+	}
+	writeJSON(w, http.StatusOK, HTTPWriteResult{Written: written})
+}
+
+// scopeFromRequest parses the query parameters scope and scope_id. Without
+// parameters the default scope will be returned.
+func scopeFromRequest(r *http.Request) (ArgFunc, error) {
+	q := r.URL.Query()
+	sg := GetScopeGroup(q.Get("scope"))
+	if sg == ScopeDefaultID {
+		if s := q.Get("scope"); s != "" && s != ScopeRangeDefault {
+			return nil, ErrInvalidScope
+		}
+		return Scope(ScopeDefaultID, nil), nil
+	}
+	id, err := strconv.ParseInt(q.Get("scope_id"), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidScope
+	}
+	return Scope(sg, ScopeID(id)), nil
+}
+
+// writeJSON encodes v with the status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("config.writeJSON", "err", err)
+	}
+}
+
+// writeJSONError writes {"error":"...","path":"..."}. The path is optional.
+func writeJSONError(w http.ResponseWriter, code int, err error, path ...string) {
+	e := map[string]string{"error": err.Error()}
+	if len(path) > 0 {
+		e["path"] = path[0]
+	}
+	writeJSON(w, code, e)
+}
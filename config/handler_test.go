@@ -0,0 +1,349 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var handlerTestSections = config.NewConfiguration(
+	&config.Section{
+		ID:    "web",
+		Scope: config.ScopePermAll,
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID:    "cookie",
+				Scope: config.ScopePermAll,
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `web/cookie/cookie_path`,
+						ID:      "cookie_path",
+						Type:    config.TypeText,
+						Scope:   config.ScopePermAll,
+						Default: "/",
+					},
+					&config.Field{
+						// Path: `web/cookie/cookie_httponly`,
+						ID:      "cookie_httponly",
+						Type:    config.TypeSelect,
+						Scope:   config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID),
+						Default: true,
+					},
+					&config.Field{
+						// Path: `web/cookie/secret`,
+						ID:      "secret",
+						Type:    config.TypeObscure,
+						Scope:   config.ScopePermAll,
+						Default: "s3cr3t",
+					},
+				},
+			},
+		},
+	},
+	&config.Section{
+		ID:         "system",
+		Permission: config.PermRead,
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID: "smtp",
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `system/smtp/host`,
+						ID:      "host",
+						Type:    config.TypeText,
+						Default: "localhost",
+					},
+				},
+			},
+		},
+	},
+	&config.Section{
+		ID:         "payment",
+		Permission: config.PermWrite,
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID: "account",
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `payment/account/merchant_id`,
+						ID:      "merchant_id",
+						Type:    config.TypeText,
+						Default: "m1",
+					},
+				},
+			},
+		},
+	},
+)
+
+func handlerTestRequest(h http.Handler, method, url, body string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		panic(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTestHandler(perm uint) (*config.Manager, *config.HTTPHandler) {
+	m := config.NewManager()
+	m.ApplyDefaults(handlerTestSections)
+	return m, config.NewHTTPHandler(handlerTestSections, m, m, config.SetHTTPHandlerACL(func(_ *http.Request) uint {
+		return perm
+	}))
+}
+
+func TestHTTPHandlerSections(t *testing.T) {
+	_, h := newTestHandler(config.PermRead)
+	rec := handlerTestRequest(h, "GET", "/sections", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	var ss config.SectionSlice
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ss))
+	assert.Len(t, ss, 2)
+	assert.Equal(t, "web", ss[0].ID)
+	assert.Equal(t, "system", ss[1].ID)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, handlerTestRequest(h, "DELETE", "/sections", "").Code)
+	assert.Equal(t, http.StatusNotFound, handlerTestRequest(h, "GET", "/groups", "").Code)
+}
+
+func TestHTTPHandlerGetValues(t *testing.T) {
+	m, h := newTestHandler(config.PermRead)
+	assert.NoError(t, m.Write(config.Path("web/cookie/cookie_path"), config.Value("/shop"), config.ScopeStore(config.ScopeID(2)), config.NoBubble()))
+
+	rec := handlerTestRequest(h, "GET", "/values?scope=stores&scope_id=2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var vals []config.HTTPValue
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vals))
+	assert.Exactly(t, []config.HTTPValue{
+		{Path: "web/cookie/cookie_path", Value: "/shop", Scope: "stores"},
+		{Path: "web/cookie/secret", Value: config.ObscureMask, Scope: "default"},
+		{Path: "system/smtp/host", Value: "localhost", Scope: "default"},
+	}, vals)
+
+	assert.Equal(t, http.StatusBadRequest, handlerTestRequest(h, "GET", "/values?scope=stores", "").Code)
+	assert.Equal(t, http.StatusBadRequest, handlerTestRequest(h, "GET", "/values?scope=galaxy&scope_id=1", "").Code)
+}
+
+func TestHTTPHandlerPostValues(t *testing.T) {
+	m, h := newTestHandler(config.PermRead | config.PermWrite)
+
+	rec := handlerTestRequest(h, "POST", "/values?scope=websites&scope_id=1", `{"web/cookie/cookie_path":"/de","web/cookie/cookie_httponly":false}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "{\"written\":[\"web/cookie/cookie_httponly\",\"web/cookie/cookie_path\"]}\n", rec.Body.String())
+
+	s, err := m.String(config.Path("web/cookie/cookie_path"), config.ScopeWebsite(config.ScopeID(1)))
+	assert.NoError(t, err)
+	assert.Exactly(t, "/de", s)
+	s, err = m.String(config.Path("web/cookie/cookie_path"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "/", s, "must not bubble into the default scope")
+
+	tests := []struct {
+		url, body string
+		wantCode  int
+		wantErr   error
+	}{
+		{"/values?scope=stores&scope_id=1", `{"web/cookie/cookie_httponly":false}`, http.StatusForbidden, config.ErrScopeNotAllowed},
+		{"/values", `{"system/smtp/host":"mail"}`, http.StatusForbidden, config.ErrPermissionDenied},
+		{"/values", `{"web/cookie/not_found":1}`, http.StatusNotFound, config.ErrFieldNotFound},
+		{"/values", `{"web/cookie/cookie_path":`, http.StatusBadRequest, nil},
+		// the first path is valid but must not be written
+		{"/values", `{"web/cookie/cookie_path":"/x","system/smtp/host":"mail"}`, http.StatusForbidden, config.ErrPermissionDenied},
+	}
+	for _, test := range tests {
+		rec := handlerTestRequest(h, "POST", test.url, test.body)
+		assert.Equal(t, test.wantCode, rec.Code, test.body)
+		if test.wantErr != nil {
+			assert.Contains(t, rec.Body.String(), test.wantErr.Error())
+		}
+	}
+	s, err = m.String(config.Path("web/cookie/cookie_path"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "/", s)
+
+	// write only section
+	rec = handlerTestRequest(h, "POST", "/values", `{"payment/account/merchant_id":"m2"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, handlerTestRequest(h, "GET", "/values", "").Body.String(), "merchant_id")
+}
+
+func TestHTTPHandlerPostObscureMask(t *testing.T) {
+	m, h := newTestHandler(config.PermRead | config.PermWrite)
+	assert.NoError(t, m.Write(config.Path("web/cookie/secret"), config.Value("t0p"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+
+	// the masked value of GET must not overwrite the secret
+	rec := handlerTestRequest(h, "POST", "/values?scope=websites&scope_id=1", `{"web/cookie/secret":"`+config.ObscureMask+`","web/cookie/cookie_path":"/de"}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "{\"written\":[\"web/cookie/cookie_path\"]}\n", rec.Body.String())
+	assert.Exactly(t, "t0p", m.GetString(config.Path("web/cookie/secret"), config.ScopeWebsite(config.ScopeID(1))))
+
+	rec = handlerTestRequest(h, "POST", "/values?scope=websites&scope_id=1", `{"web/cookie/secret":"n3w"}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Exactly(t, "n3w", m.GetString(config.Path("web/cookie/secret"), config.ScopeWebsite(config.ScopeID(1))))
+}
+
+func TestHTTPHandlerPostCheckAll(t *testing.T) {
+	// the strict Manager does not know the path web/cookie/cookie_path
+	m := config.NewManager(config.SetManagerStrict(config.NewConfiguration(
+		&config.Section{
+			ID: "web",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "cookie",
+					Fields: config.FieldSlice{
+						&config.Field{ID: "cookie_httponly", Type: config.TypeSelect},
+						&config.Field{ID: "secret", Type: config.TypeObscure},
+					},
+				},
+			},
+		},
+	)))
+	h := config.NewHTTPHandler(handlerTestSections, m, m, config.SetHTTPHandlerACL(func(_ *http.Request) uint {
+		return config.PermRead | config.PermWrite
+	}))
+
+	rec := handlerTestRequest(h, "POST", "/values", `{"web/cookie/cookie_httponly":"0","web/cookie/cookie_path":"/x","web/cookie/secret":"n3w"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "{\"error\":\""+config.ErrFieldNotFound.Error()+"\",\"path\":\"web/cookie/cookie_path\"}\n", rec.Body.String())
+	assert.False(t, m.IsSet(config.Path("web/cookie/cookie_httponly")), "must not be written")
+	assert.False(t, m.IsSet(config.Path("web/cookie/secret")))
+}
+
+// handlerFailWriter fails after n writes like a broken database connection.
+type handlerFailWriter struct {
+	n int
+}
+
+var errHandlerFailWriter = errors.New("Connection lost")
+
+func (fw *handlerFailWriter) Write(_ ...config.ArgFunc) error {
+	if fw.n == 0 {
+		return errHandlerFailWriter
+	}
+	fw.n--
+	return nil
+}
+
+func TestHTTPHandlerPostPartialWrite(t *testing.T) {
+	m := config.NewManager()
+	h := config.NewHTTPHandler(handlerTestSections, m, &handlerFailWriter{n: 1}, config.SetHTTPHandlerACL(func(_ *http.Request) uint {
+		return config.PermRead | config.PermWrite
+	}))
+
+	rec := handlerTestRequest(h, "POST", "/values", `{"web/cookie/cookie_httponly":"0","web/cookie/cookie_path":"/x","web/cookie/secret":"n3w"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var res config.HTTPWriteResult
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Exactly(t, config.HTTPWriteResult{
+		Written: []string{"web/cookie/cookie_httponly"},
+		Error:   errHandlerFailWriter.Error(),
+		Path:    "web/cookie/cookie_path",
+	}, res)
+}
+
+func TestHTTPHandlerScopePerm(t *testing.T) {
+	ss := config.NewConfiguration(
+		&config.Section{
+			ID:    "design",
+			Scope: config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID),
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID:    "head",
+					Scope: config.ScopePermAll,
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `design/head/title`,
+							ID:      "title",
+							Type:    config.TypeText,
+							Default: "Shop",
+						},
+					},
+				},
+				&config.Group{
+					ID:    "footer",
+					Scope: config.NewScopePerm(config.ScopeDefaultID),
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `design/footer/copyright`,
+							ID:      "copyright",
+							Type:    config.TypeText,
+							Scope:   config.ScopePermAll,
+							Default: "CoreStore",
+						},
+					},
+				},
+			},
+		},
+	)
+	m := config.NewManager()
+	m.ApplyDefaults(ss)
+	h := config.NewHTTPHandler(ss, m, m, config.SetHTTPHandlerACL(func(_ *http.Request) uint {
+		return config.PermRead | config.PermWrite
+	}))
+
+	tests := []struct {
+		url       string
+		wantPaths []string
+	}{
+		{"/values", []string{"design/head/title", "design/footer/copyright"}},
+		{"/values?scope=websites&scope_id=1", []string{"design/head/title"}},
+		{"/values?scope=stores&scope_id=1", []string{}},
+	}
+	for i, test := range tests {
+		var vals []config.HTTPValue
+		assert.NoError(t, json.Unmarshal(handlerTestRequest(h, "GET", test.url, "").Body.Bytes(), &vals), "Index %d", i)
+		paths := []string{}
+		for _, v := range vals {
+			paths = append(paths, v.Path)
+		}
+		assert.Exactly(t, test.wantPaths, paths, "Index %d", i)
+	}
+
+	postTests := []struct {
+		url, body string
+		wantCode  int
+	}{
+		{"/values?scope=websites&scope_id=1", `{"design/head/title":"Shop DE"}`, http.StatusOK},
+		{"/values?scope=websites&scope_id=1", `{"design/footer/copyright":"CS"}`, http.StatusForbidden},
+		{"/values?scope=stores&scope_id=1", `{"design/head/title":"Shop CH"}`, http.StatusForbidden},
+	}
+	for i, test := range postTests {
+		rec := handlerTestRequest(h, "POST", test.url, test.body)
+		assert.Equal(t, test.wantCode, rec.Code, "Index %d", i)
+		if test.wantCode == http.StatusForbidden {
+			assert.Contains(t, rec.Body.String(), config.ErrScopeNotAllowed.Error(), "Index %d", i)
+		}
+	}
+}
+
+func TestHTTPHandlerReadOnlyDefault(t *testing.T) {
+	m := config.NewManager()
+	h := config.NewHTTPHandler(handlerTestSections, m, m)
+	rec := handlerTestRequest(h, "POST", "/values", `{"web/cookie/cookie_path":"/x"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), config.ErrPermissionDenied.Error())
+}
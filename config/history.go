@@ -0,0 +1,292 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// ErrHistoryNotFound gets returned when a history entry or a changeset cannot
+// be found.
+var ErrHistoryNotFound = errors.New("History not found")
+
+// TableHistorySchema creates the history table. The name must match
+// TableHistory.Name.
+const TableHistorySchema = "CREATE TABLE IF NOT EXISTS `csfw_config_history` (\n" +
+	"  `history_id` int(10) unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `changeset` varchar(32) NOT NULL,\n" +
+	"  `scope` varchar(8) NOT NULL DEFAULT 'default',\n" +
+	"  `scope_id` int(11) NOT NULL DEFAULT '0',\n" +
+	"  `path` varchar(255) NOT NULL,\n" +
+	"  `old_value` text,\n" +
+	"  `new_value` text,\n" +
+	"  `actor` varchar(255) NOT NULL DEFAULT '',\n" +
+	"  `created_at` datetime NOT NULL,\n" +
+	"  PRIMARY KEY (`history_id`),\n" +
+	"  KEY `IDX_CSFW_CONFIG_HISTORY_PATH` (`path`,`scope`,`scope_id`),\n" +
+	"  KEY `IDX_CSFW_CONFIG_HISTORY_CHANGESET` (`changeset`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8"
+
+// TableHistory defines the table which records all changes of core_config_data,
+// see TableHistorySchema. Change the name to use a table prefix.
+var TableHistory = csdb.NewTableStructure(
+	"csfw_config_history",
+	[]string{"history_id"},
+	[]string{"changeset", "scope", "scope_id", "path", "old_value", "new_value", "actor", "created_at"},
+)
+
+// DefaultHistoryActor will be recorded when no actor has been set.
+const DefaultHistoryActor = "system"
+
+type (
+	// TableHistorySlice contains history entries, the newest first.
+	TableHistorySlice []*TableHistoryEntry
+	// TableHistoryEntry is one row of the table TableHistory. A NULL value
+	// means the path has not been set in that scope.
+	TableHistoryEntry struct {
+		HistoryID int64          `db:"history_id"`
+		Changeset string         `db:"changeset"`
+		Scope     string         `db:"scope"`
+		ScopeID   int64          `db:"scope_id"`
+		Path      string         `db:"path"`
+		OldValue  dbr.NullString `db:"old_value"`
+		NewValue  dbr.NullString `db:"new_value"`
+		Actor     string         `db:"actor"`
+		CreatedAt dbr.NullTime   `db:"created_at"`
+	}
+
+	// HistoryWriter records each write in the table TableHistory with the
+	// value of core_config_data before and after the write. It wraps a Writer
+	// which must persist the values into core_config_data within the same
+	// transaction, mostly the DBWriter, so the history gets recorded
+	// atomically with the write. All writes of a HistoryWriter created by
+	// NewChangeset() share one changeset ID, otherwise each write gets its own
+	// changeset.
+	HistoryWriter struct {
+		tx        *dbr.Tx
+		w         Writer
+		actor     string
+		changeset string
+	}
+
+	// HistoryWriterOption option func for NewHistoryWriter()
+	HistoryWriterOption func(*HistoryWriter)
+)
+
+var _ Writer = (*HistoryWriter)(nil)
+
+// SetHistoryWriterActor sets the actor, e.g. the admin user name, which will be
+// recorded for each write. Default: DefaultHistoryActor.
+func SetHistoryWriterActor(actor string) HistoryWriterOption {
+	return func(hw *HistoryWriter) { hw.actor = actor }
+}
+
+// NewHistoryWriter creates a new Writer which records the changes of w in the
+// table TableHistory. w must write with the same transaction tx.
+func NewHistoryWriter(tx *dbr.Tx, w Writer, opts ...HistoryWriterOption) *HistoryWriter {
+	hw := &HistoryWriter{
+		tx:    tx,
+		w:     w,
+		actor: DefaultHistoryActor,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(hw)
+		}
+	}
+	return hw
+}
+
+// NewChangeset returns a HistoryWriter for the actor whose writes all belong
+// to one new changeset, e.g. all values of one submitted admin form.
+func (hw *HistoryWriter) NewChangeset(actor string) *HistoryWriter {
+	return &HistoryWriter{
+		tx:        hw.tx,
+		w:         hw.w,
+		actor:     actor,
+		changeset: newChangesetID(),
+	}
+}
+
+// Changeset returns the ID of the changeset or an empty string if each write
+// gets its own changeset.
+func (hw *HistoryWriter) Changeset() string {
+	return hw.changeset
+}
+
+// Write writes the value with the wrapped Writer and records the old and the
+// new value of core_config_data. If the value bubbles the default scope will
+// also be recorded but only if the wrapped Writer has changed it, see
+// SetDBWriterBubble(). Same arguments as DBWriter.Write.
+func (hw *HistoryWriter) Write(o ...ArgFunc) error {
+	a := newArg(o...)
+	if a.p == "" {
+		return ErrPathEmpty
+	}
+
+	entries := make(TableHistorySlice, 0, 2)
+	entries = append(entries, &TableHistoryEntry{Scope: a.scopeRange(), ScopeID: a.scopeIDInt64(), Path: a.p})
+	if a.isBubbling() && false == a.isDefault() {
+		entries = append(entries, &TableHistoryEntry{Scope: ScopeRangeDefault, ScopeID: 0, Path: a.p})
+	}
+
+	for _, e := range entries {
+		old, err := selectCoreConfigValue(hw.tx, e.Scope, e.ScopeID, e.Path)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		e.OldValue = old
+	}
+
+	if err := hw.w.Write(o...); err != nil {
+		return err
+	}
+
+	cs := hw.changeset
+	if cs == "" {
+		cs = newChangesetID()
+	}
+	now := time.Now()
+	tn := TableHistory.Name
+	for i, e := range entries {
+		nv, err := selectCoreConfigValue(hw.tx, e.Scope, e.ScopeID, e.Path)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		if i > 0 && nv == e.OldValue {
+			// the Writer has not bubbled the value into the default scope
+			continue
+		}
+		if log.IsDebug() {
+			log.Debug("HistoryWriter=Write", "changeset", cs, "actor", hw.actor, "scope", e.Scope, "scopeID", e.ScopeID, "path", e.Path)
+		}
+		if _, err := hw.tx.
+			InsertInto(tn).
+			Columns("changeset", "scope", "scope_id", "path", "old_value", "new_value", "actor", "created_at").
+			Values(cs, e.Scope, e.ScopeID, e.Path, e.OldValue, nv, hw.actor, now).
+			Exec(); err != nil {
+			return errgo.Mask(err)
+		}
+	}
+	return nil
+}
+
+// History returns all recorded changes of a path in all scopes, the newest first.
+func (hw *HistoryWriter) History(path string) (TableHistorySlice, error) {
+	return hw.loadHistory("main_table.path = ?", path)
+}
+
+// ChangesetHistory returns all recorded changes of a changeset, the newest first.
+func (hw *HistoryWriter) ChangesetHistory(changeset string) (TableHistorySlice, error) {
+	return hw.loadHistory("main_table.changeset = ?", changeset)
+}
+
+// RollbackPath restores the path and scope of a history entry to the value
+// after that change was made. The rollback will be recorded as a new
+// changeset of the actor whose ID gets returned.
+func (hw *HistoryWriter) RollbackPath(historyID int64, actor string) (changeset string, err error) {
+	hs, err := hw.loadHistory("main_table.history_id = ?", historyID)
+	if err != nil {
+		return "", errgo.Mask(err)
+	}
+	cw := hw.NewChangeset(actor)
+	if err := cw.restore(hs[0], hs[0].NewValue); err != nil {
+		return "", err
+	}
+	return cw.changeset, nil
+}
+
+// RollbackChangeset reverts all changes of a changeset by restoring the old
+// values in the reverse order of the writes. The rollback will be recorded as
+// a new changeset of the actor whose ID gets returned.
+func (hw *HistoryWriter) RollbackChangeset(changeset, actor string) (string, error) {
+	hs, err := hw.ChangesetHistory(changeset)
+	if err != nil {
+		return "", errgo.Mask(err)
+	}
+	cw := hw.NewChangeset(actor)
+	for _, e := range hs {
+		if err := cw.restore(e, e.OldValue); err != nil {
+			return "", err
+		}
+	}
+	return cw.changeset, nil
+}
+
+// restore writes the stored val as a raw value into the scope and path of the
+// entry. NULL writes nil which deletes the row, see DBWriter.Write.
+func (hw *HistoryWriter) restore(e *TableHistoryEntry, val dbr.NullString) error {
+	var v interface{}
+	if val.Valid {
+		v = val.String
+	}
+	if log.IsDebug() {
+		log.Debug("HistoryWriter=restore", "historyID", e.HistoryID, "changeset", hw.changeset, "scope", e.Scope, "scopeID", e.ScopeID, "path", e.Path)
+	}
+	return hw.Write(Path(e.Path), Value(v), Scope(GetScopeGroup(e.Scope), ScopeID(e.ScopeID)), NoBubble(), RawValue())
+}
+
+// loadHistory loads the entries matching the where condition, the newest first.
+// Returns ErrHistoryNotFound if there are no entries.
+func (hw *HistoryWriter) loadHistory(where string, arg interface{}) (TableHistorySlice, error) {
+	sb, err := TableHistory.Select(hw.tx)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	var hs TableHistorySlice
+	if _, err := sb.Where(where, arg).OrderDir("main_table.history_id", false).LoadStructs(&hs); err != nil {
+		return nil, errgo.Mask(err)
+	}
+	if len(hs) == 0 {
+		return nil, ErrHistoryNotFound
+	}
+	return hs, nil
+}
+
+// selectCoreConfigValue returns the value of a row in core_config_data. A
+// missing row returns NULL.
+func selectCoreConfigValue(dbrSess dbr.SessionRunner, scope string, scopeID int64, path string) (dbr.NullString, error) {
+	var val dbr.NullString
+	err := dbrSess.
+		Select("value").
+		From(TableCollection.Name(TableIndexCoreConfigData)).
+		Where("scope = ?", scope).
+		Where("scope_id = ?", scopeID).
+		Where("path = ?", path).
+		LoadValue(&val)
+	if err == dbr.ErrNotFound {
+		return dbr.NullString{}, nil
+	}
+	return val, errgo.Mask(err)
+}
+
+// newChangesetID returns a random hex encoded ID.
+func newChangesetID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand should never fail, fall back to the time
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,104 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/stretchr/testify/assert"
+)
+
+type errWriter struct{}
+
+func (errWriter) Write(_ ...config.ArgFunc) error { return errors.New("Write failed") }
+
+func TestHistoryWriterChangeset(t *testing.T) {
+	hw := config.NewHistoryWriter(new(dbr.Tx), errWriter{}, config.SetHistoryWriterActor("alice"))
+	assert.Exactly(t, "", hw.Changeset())
+
+	cs1 := hw.NewChangeset("bob")
+	cs2 := hw.NewChangeset("bob")
+	assert.Len(t, cs1.Changeset(), 16)
+	assert.NotEqual(t, cs1.Changeset(), cs2.Changeset())
+
+	assert.EqualError(t, hw.Write(config.Value(1)), config.ErrPathEmpty.Error())
+}
+
+func TestHistoryWriterRollbackDB(t *testing.T) {
+	db := csdb.MustConnectTest()
+	defer db.Close()
+	if _, err := db.Exec(config.TableHistorySchema); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := dbr.NewConnection(db, nil).NewSession(nil).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	m := config.NewManager()
+	hw := config.NewHistoryWriter(tx, config.NewDBWriter(tx, config.SetDBWriterManager(m)))
+	const path = "web/secure/base_url"
+	store1 := config.ScopeStore(config.ScopeID(1))
+
+	assert.NoError(t, hw.Write(config.Path(path), config.Value("https://v1.io/"), store1, config.NoBubble()))
+	cs := hw.NewChangeset("alice")
+	assert.NoError(t, cs.Write(config.Path(path), config.Value("https://v2.io/"), store1, config.NoBubble()))
+	assert.NoError(t, cs.Write(config.Path("web/secure/use_in_frontend"), config.Value(true), store1, config.NoBubble()))
+
+	hs, err := hw.History(path)
+	assert.NoError(t, err)
+	if assert.Len(t, hs, 2) {
+		assert.Exactly(t, "alice", hs[0].Actor)
+		assert.Exactly(t, cs.Changeset(), hs[0].Changeset)
+		assert.Exactly(t, "https://v1.io/", hs[0].OldValue.String)
+		assert.Exactly(t, "https://v2.io/", hs[0].NewValue.String)
+		assert.Exactly(t, config.DefaultHistoryActor, hs[1].Actor)
+		assert.False(t, hs[1].OldValue.Valid)
+		assert.True(t, hs[1].CreatedAt.Valid)
+	}
+
+	rcs, err := hw.RollbackChangeset(cs.Changeset(), "bob")
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://v1.io/", selectStoreValue(t, tx, path).String)
+	rhs, err := hw.ChangesetHistory(rcs)
+	assert.NoError(t, err)
+	assert.Len(t, rhs, 2)
+
+	// the path was not set before the changeset so the row must be deleted
+	assert.False(t, selectStoreValue(t, tx, "web/secure/use_in_frontend").Valid)
+
+	_, err = hw.RollbackPath(hs[0].HistoryID, "bob")
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://v2.io/", selectStoreValue(t, tx, path).String)
+	// the Manager receives the values after the commit of the DBWriter
+	assert.False(t, m.IsSet(config.Path(path), store1))
+
+	// the DBWriter does not bubble so the default scope must not be recorded
+	bcs := hw.NewChangeset("carol")
+	assert.NoError(t, bcs.Write(config.Path("web/secure/offloader_header"), config.Value("X-Proto"), store1))
+	bhs, err := hw.ChangesetHistory(bcs.Changeset())
+	assert.NoError(t, err)
+	if assert.Len(t, bhs, 1) {
+		assert.Exactly(t, config.ScopeRangeStores, bhs[0].Scope)
+	}
+
+	_, err = hw.ChangesetHistory("unknown")
+	assert.EqualError(t, err, config.ErrHistoryNotFound.Error())
+}
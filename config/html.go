@@ -0,0 +1,287 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// ObscureMask gets displayed in a TypeObscure field which contains a value.
+// The real value never leaves the server.
+const ObscureMask = "******"
+
+type (
+	// FieldHTML contains the data to render the input element of a Field. Used
+	// in FieldTyper.ToHTML().
+	FieldHTML struct {
+		// ID HTML id attribute, the path with underscores.
+		ID string
+		// Name HTML name attribute, config[<path>]
+		Name  string
+		Field *Field
+		// Value current value in the requested scope, converted to a string
+		// like in core_config_data. Slices are comma separated.
+		Value string
+		// Options of the SourceModel, if any.
+		Options ValueLabelSlice
+		// Disabled gets set if the value has been inherited from a parent scope.
+		Disabled bool
+	}
+
+	// HTMLForm renders the Sections, Groups and Fields of a SectionSlice as an
+	// HTML form for one scope. Fields whose ScopePerm excludes the scope and
+	// invisible Fields will be skipped. In the website and store scope each
+	// Field gets a checkbox to inherit the value of the parent scope.
+	HTMLForm struct {
+		r      Reader
+		sg     ScopeGroup
+		id     ScopeIDer
+		action string
+	}
+
+	// HTMLFormOption option func for NewHTMLForm()
+	HTMLFormOption func(*HTMLForm)
+)
+
+// SetHTMLFormAction sets the action attribute of the form.
+func SetHTMLFormAction(url string) HTMLFormOption {
+	return func(hf *HTMLForm) { hf.action = url }
+}
+
+// NewHTMLForm creates a new form renderer which reads the values from r in the
+// scope sg with the ID id. For the default scope id can be nil.
+func NewHTMLForm(r Reader, sg ScopeGroup, id ScopeIDer, opts ...HTMLFormOption) *HTMLForm {
+	if id == nil {
+		sg = ScopeDefaultID
+	}
+	hf := &HTMLForm{
+		r:  r,
+		sg: sg,
+		id: id,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(hf)
+		}
+	}
+	return hf
+}
+
+// Render writes the form into w.
+func (hf *HTMLForm) Render(w io.Writer, ss SectionSlice) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<form method=\"post\" action=\"%s\">\n", html.EscapeString(hf.action))
+	fmt.Fprintf(bw, "<input type=\"hidden\" name=\"scope\" value=\"%s\">\n", newArg(Scope(hf.sg, hf.id)).scopeRange())
+	if hf.id != nil {
+		fmt.Fprintf(bw, "<input type=\"hidden\" name=\"scope_id\" value=\"%d\">\n", hf.id.ScopeID())
+	}
+	for _, s := range ss {
+		if s == nil || false == hf.visible(s.Scope, VisibleAbsent) {
+			continue
+		}
+		var gBuf bytes.Buffer
+		for _, g := range s.Groups {
+			if g == nil || false == hf.visible(g.Scope, VisibleAbsent) {
+				continue
+			}
+			var fBuf bytes.Buffer
+			for _, f := range g.Fields {
+				if f == nil || false == hf.visible(f.Scope, f.Visible) {
+					continue
+				}
+				hf.writeField(&fBuf, s.ID+PS+g.ID+PS+f.ID, f)
+			}
+			if fBuf.Len() == 0 {
+				continue
+			}
+			fmt.Fprintf(&gBuf, "<fieldset id=\"group_%s_%s\">\n<legend>%s</legend>\n", html.EscapeString(s.ID), html.EscapeString(g.ID), html.EscapeString(g.Label))
+			if g.Comment != "" {
+				fmt.Fprintf(&gBuf, "<p class=\"comment\">%s</p>\n", html.EscapeString(g.Comment))
+			}
+			fBuf.WriteTo(&gBuf)
+			gBuf.WriteString("</fieldset>\n")
+		}
+		if gBuf.Len() == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "<fieldset id=\"section_%s\">\n<legend>%s</legend>\n", html.EscapeString(s.ID), html.EscapeString(s.Label))
+		gBuf.WriteTo(bw)
+		bw.WriteString("</fieldset>\n")
+	}
+	bw.WriteString("</form>\n")
+	return errgo.Mask(bw.Flush())
+}
+
+// visible checks the ScopePerm and the Visible flag. An empty ScopePerm allows
+// all scopes.
+func (hf *HTMLForm) visible(p ScopePerm, v Visible) bool {
+	return v != VisibleNo && (p == 0 || p.Has(hf.sg))
+}
+
+// writeField writes the row of a Field with its label, input element, comment
+// and the inherit checkbox.
+func (hf *HTMLForm) writeField(buf *bytes.Buffer, path string, f *Field) {
+	if f.Type == nil {
+		return
+	}
+	fh := hf.FieldHTML(path, f)
+	id := html.EscapeString(fh.ID)
+	if f.Type.Type() == TypeHidden {
+		buf.Write(f.Type.ToHTML(fh))
+		buf.WriteByte('\n')
+		return
+	}
+
+	fmt.Fprintf(buf, "<div class=\"field\" id=\"row_%s\">\n", id)
+	fmt.Fprintf(buf, "<label for=\"%s\">%s</label>\n", id, html.EscapeString(f.Label))
+	buf.Write(f.Type.ToHTML(fh))
+	buf.WriteByte('\n')
+	if f.Comment != "" {
+		fmt.Fprintf(buf, "<p class=\"note\">%s</p>\n", html.EscapeString(f.Comment))
+	}
+	if hf.sg != ScopeDefaultID {
+		checked := ""
+		if fh.Disabled {
+			checked = " checked"
+		}
+		label := "Use Default"
+		if hf.sg == ScopeStoreID || hf.sg == ScopeGroupID {
+			label = "Use Website"
+		}
+		fmt.Fprintf(buf, "<input type=\"checkbox\" class=\"inherit\" id=\"%s_inherit\" name=\"inherit[%s]\" value=\"1\"%s>\n", id, html.EscapeString(path), checked)
+		fmt.Fprintf(buf, "<label for=\"%s_inherit\">%s</label>\n", id, label)
+	}
+	buf.WriteString("</div>\n")
+}
+
+// FieldHTML collects the value and the options of a Field in the scope of the
+// form. A value is inherited if it has been found in another scope.
+func (hf *HTMLForm) FieldHTML(path string, f *Field) FieldHTML {
+	fh := FieldHTML{
+		ID:    strings.Replace(path, PS, "_", -1),
+		Name:  "config[" + path + "]",
+		Field: f,
+	}
+
+	v, vsg, err := hf.r.Lookup(Path(path), Scope(hf.sg, hf.id))
+	switch {
+	case err == nil:
+		if dv, err := toDBValue(v); err == nil {
+			fh.Value = dv.String
+		} else {
+			log.Error("HTMLForm=FieldHTML.toDBValue", "err", err, "path", path)
+		}
+		fh.Disabled = hf.sg != ScopeDefaultID && vsg != hf.sg
+	case err == ErrKeyNotFound:
+		fh.Disabled = hf.sg != ScopeDefaultID
+	default:
+		log.Error("HTMLForm=FieldHTML.Lookup", "err", err, "path", path)
+	}
+
+	if f.SourceModel != nil {
+		fh.Options = sourceOptions(f.SourceModel, ModelConstructor{Scope: hf.id, ScopeGroup: hf.sg, Path: path, ConfigReader: hf.r})
+	}
+	return fh
+}
+
+// ToHTML renders the input element of the built-in field types. TypeCustom
+// returns nil and requires an own FieldTyper.
+// @see \Magento\Framework\Data\Form\Element\AbstractElement
+func (i FieldType) ToHTML(fh FieldHTML) []byte {
+	var buf bytes.Buffer
+	id, name, val := html.EscapeString(fh.ID), html.EscapeString(fh.Name), html.EscapeString(fh.Value)
+	disabled := ""
+	if fh.Disabled {
+		disabled = " disabled"
+	}
+
+	switch i {
+	case TypeText:
+		fmt.Fprintf(&buf, `<input type="text" id="%s" name="%s" value="%s"%s>`, id, name, val, disabled)
+	case TypeTextarea:
+		fmt.Fprintf(&buf, `<textarea id="%s" name="%s"%s>%s</textarea>`, id, name, disabled, val)
+	case TypeSelect:
+		fmt.Fprintf(&buf, `<select id="%s" name="%s"%s>`, id, name, disabled)
+		writeHTMLOptions(&buf, fh.Options, []string{fh.Value})
+		buf.WriteString(`</select>`)
+	case TypeMultiselect:
+		fmt.Fprintf(&buf, `<select id="%s" name="%s[]" multiple%s>`, id, name, disabled)
+		writeHTMLOptions(&buf, fh.Options, strings.Split(fh.Value, ","))
+		buf.WriteString(`</select>`)
+	case TypeObscure:
+		if val != "" {
+			val = ObscureMask
+		}
+		fmt.Fprintf(&buf, `<input type="password" id="%s" name="%s" value="%s" autocomplete="off"%s>`, id, name, val, disabled)
+	case TypeTime:
+		// value format: hour,minute,second
+		parts := strings.Split(fh.Value, ",")
+		for j, max := range [...]int{24, 60, 60} {
+			cur := "00"
+			if j < len(parts) && parts[j] != "" {
+				cur = strings.TrimSpace(parts[j])
+				if len(cur) == 1 {
+					cur = "0" + cur
+				}
+			}
+			fmt.Fprintf(&buf, `<select id="%s_%d" name="%s[]"%s>`, id, j, name, disabled)
+			for k := 0; k < max; k++ {
+				o := fmt.Sprintf("%02d", k)
+				sel := ""
+				if o == cur {
+					sel = " selected"
+				}
+				fmt.Fprintf(&buf, `<option value="%s"%s>%s</option>`, o, sel, o)
+			}
+			buf.WriteString(`</select>`)
+		}
+	case TypeLabel:
+		fmt.Fprintf(&buf, `<span id="%s">%s</span>`, id, val)
+	case TypeHidden:
+		fmt.Fprintf(&buf, `<input type="hidden" id="%s" name="%s" value="%s">`, id, name, val)
+	case TypeImage:
+		if val != "" {
+			fmt.Fprintf(&buf, `<img src="%s" alt="%s">`, val, html.EscapeString(fh.Field.Label))
+		}
+		fmt.Fprintf(&buf, `<input type="file" id="%s" name="%s" accept="image/*"%s>`, id, name, disabled)
+	case TypeButton:
+		fmt.Fprintf(&buf, `<button type="button" id="%s" name="%s"%s>%s</button>`, id, name, disabled, html.EscapeString(fh.Field.Label))
+	default:
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// writeHTMLOptions writes the option elements and selects the values.
+func writeHTMLOptions(buf *bytes.Buffer, opts ValueLabelSlice, selected []string) {
+	for _, o := range opts {
+		sel := ""
+		for _, s := range selected {
+			if strings.TrimSpace(s) == o.Value {
+				sel = " selected"
+				break
+			}
+		}
+		fmt.Fprintf(buf, `<option value="%s"%s>%s</option>`, html.EscapeString(o.Value), sel, html.EscapeString(o.Label))
+	}
+}
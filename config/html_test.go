@@ -0,0 +1,180 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var htmlTestSections = config.NewConfiguration(
+	&config.Section{
+		ID:    "web",
+		Label: "Web",
+		Scope: config.ScopePermAll,
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID:      "cookie",
+				Label:   "Cookie & Session",
+				Comment: "Cookie settings",
+				Scope:   config.ScopePermAll,
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `web/cookie/cookie_path`,
+						ID:      "cookie_path",
+						Label:   "Cookie Path",
+						Comment: "<b>escaped</b>",
+						Type:    config.TypeText,
+						Scope:   config.ScopePermAll,
+						Default: "/",
+					},
+					&config.Field{
+						// Path: `web/cookie/cookie_httponly`,
+						ID:          "cookie_httponly",
+						Label:       "Use HTTP Only",
+						Type:        config.TypeSelect,
+						Scope:       config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID),
+						Default:     true,
+						SourceModel: config.NewSourceYesNo(),
+					},
+					&config.Field{
+						// Path: `web/cookie/secret`,
+						ID:      "secret",
+						Label:   "Secret",
+						Type:    config.TypeObscure,
+						Scope:   config.ScopePermAll,
+						Default: "s3cr3t",
+					},
+					&config.Field{
+						// Path: `web/cookie/start`,
+						ID:      "start",
+						Label:   "Start Time",
+						Type:    config.TypeTime,
+						Scope:   config.ScopePermAll,
+						Default: "7,30,0",
+					},
+					&config.Field{
+						// Path: `web/cookie/hidden`,
+						ID:      "hidden",
+						Type:    config.TypeHidden,
+						Scope:   config.ScopePermAll,
+						Default: "h",
+					},
+					&config.Field{
+						// Path: `web/cookie/invisible`,
+						ID:      "invisible",
+						Type:    config.TypeText,
+						Visible: config.VisibleNo,
+					},
+				},
+			},
+		},
+	},
+)
+
+func TestHTMLFormDefault(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(htmlTestSections)
+
+	var buf bytes.Buffer
+	assert.NoError(t, config.NewHTMLForm(m, config.ScopeDefaultID, nil, config.SetHTMLFormAction("/admin/config/save")).Render(&buf, htmlTestSections))
+	have := buf.String()
+
+	assert.Contains(t, have, `<form method="post" action="/admin/config/save">`)
+	assert.Contains(t, have, `<input type="hidden" name="scope" value="default">`)
+	assert.Contains(t, have, `<legend>Cookie &amp; Session</legend>`)
+	assert.Contains(t, have, `<input type="text" id="web_cookie_cookie_path" name="config[web/cookie/cookie_path]" value="/">`)
+	assert.Contains(t, have, `<p class="note">&lt;b&gt;escaped&lt;/b&gt;</p>`)
+	assert.Contains(t, have, `<select id="web_cookie_cookie_httponly" name="config[web/cookie/cookie_httponly]"><option value="1" selected>Yes</option><option value="0">No</option></select>`)
+	assert.Contains(t, have, `<input type="password" id="web_cookie_secret" name="config[web/cookie/secret]" value="******" autocomplete="off">`)
+	assert.NotContains(t, have, "s3cr3t")
+	assert.Contains(t, have, `<option value="07" selected>07</option>`)
+	assert.Contains(t, have, `<option value="30" selected>30</option>`)
+	assert.Contains(t, have, `<input type="hidden" id="web_cookie_hidden" name="config[web/cookie/hidden]" value="h">`)
+	assert.NotContains(t, have, "invisible")
+	assert.NotContains(t, have, `class="inherit"`)
+}
+
+func TestHTMLFormScopes(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(htmlTestSections)
+	assert.NoError(t, m.Write(config.Path("web/cookie/cookie_path"), config.Value("/shop"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+
+	var buf bytes.Buffer
+	assert.NoError(t, config.NewHTMLForm(m, config.ScopeWebsiteID, config.ScopeID(1)).Render(&buf, htmlTestSections))
+	have := buf.String()
+	assert.Contains(t, have, `<input type="hidden" name="scope" value="websites">`)
+	assert.Contains(t, have, `<input type="hidden" name="scope_id" value="1">`)
+	assert.Contains(t, have, `<input type="text" id="web_cookie_cookie_path" name="config[web/cookie/cookie_path]" value="/shop">`)
+	assert.Contains(t, have, `<input type="checkbox" class="inherit" id="web_cookie_cookie_path_inherit" name="inherit[web/cookie/cookie_path]" value="1">`)
+	assert.Contains(t, have, `<select id="web_cookie_cookie_httponly" name="config[web/cookie/cookie_httponly]" disabled>`)
+	assert.Contains(t, have, `<input type="checkbox" class="inherit" id="web_cookie_cookie_httponly_inherit" name="inherit[web/cookie/cookie_httponly]" value="1" checked>`)
+	assert.Contains(t, have, `<label for="web_cookie_cookie_httponly_inherit">Use Default</label>`)
+
+	buf.Reset()
+	assert.NoError(t, config.NewHTMLForm(m, config.ScopeStoreID, config.ScopeID(2)).Render(&buf, htmlTestSections))
+	have = buf.String()
+	assert.NotContains(t, have, "cookie_httponly")
+	assert.Contains(t, have, `<label for="web_cookie_cookie_path_inherit">Use Website</label>`)
+}
+
+func TestHTMLFormEscapePath(t *testing.T) {
+	ss := config.NewConfiguration(
+		&config.Section{
+			ID: "web",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: `x"><script>`,
+					Fields: config.FieldSlice{
+						&config.Field{
+							ID:   "path",
+							Type: config.TypeText,
+						},
+					},
+				},
+			},
+		},
+	)
+	var buf bytes.Buffer
+	assert.NoError(t, config.NewHTMLForm(config.NewManager(), config.ScopeWebsiteID, config.ScopeID(1)).Render(&buf, ss))
+	have := buf.String()
+	assert.NotContains(t, have, "<script>")
+	assert.Contains(t, have, `<div class="field" id="row_web_x&#34;&gt;&lt;script&gt;_path">`)
+	assert.Contains(t, have, `<input type="checkbox" class="inherit" id="web_x&#34;&gt;&lt;script&gt;_path_inherit" name="inherit[web/x&#34;&gt;&lt;script&gt;/path]" value="1" checked>`)
+}
+
+func TestFieldTypeToHTML(t *testing.T) {
+	f := &config.Field{Label: "Logo"}
+	tests := []struct {
+		ft   config.FieldType
+		fh   config.FieldHTML
+		want string
+	}{
+		{config.TypeTextarea, config.FieldHTML{ID: "a_b_c", Name: "config[a/b/c]", Field: f, Value: "x<y"}, `<textarea id="a_b_c" name="config[a/b/c]">x&lt;y</textarea>`},
+		{config.TypeMultiselect, config.FieldHTML{ID: "a_b_c", Name: "config[a/b/c]", Field: f, Value: "1,3", Options: config.ValueLabelSlice{{"1", "One"}, {"2", "Two"}, {"3", "Three"}}, Disabled: true},
+			`<select id="a_b_c" name="config[a/b/c][]" multiple disabled><option value="1" selected>One</option><option value="2">Two</option><option value="3" selected>Three</option></select>`},
+		{config.TypeLabel, config.FieldHTML{ID: "a_b_c", Name: "config[a/b/c]", Field: f, Value: "v1.0"}, `<span id="a_b_c">v1.0</span>`},
+		{config.TypeImage, config.FieldHTML{ID: "a_b_c", Name: "config[a/b/c]", Field: f, Value: "/media/logo.png"}, `<img src="/media/logo.png" alt="Logo"><input type="file" id="a_b_c" name="config[a/b/c]" accept="image/*">`},
+		{config.TypeObscure, config.FieldHTML{ID: "a_b_c", Name: "config[a/b/c]", Field: f}, `<input type="password" id="a_b_c" name="config[a/b/c]" value="" autocomplete="off">`},
+		{config.TypeCustom, config.FieldHTML{ID: "a_b_c", Name: "config[a/b/c]", Field: f}, ``},
+		{config.TypeText, config.FieldHTML{ID: `a_"><b_c`, Name: `config[a/"><b/c]`, Field: f}, `<input type="text" id="a_&#34;&gt;&lt;b_c" name="config[a/&#34;&gt;&lt;b/c]" value="">`},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, string(test.ft.ToHTML(test.fh)), "Type %s", test.ft)
+	}
+}
@@ -15,15 +15,24 @@
 package config
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/corestoreio/csfw/storage/csdb"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/utils/cast"
 	"github.com/corestoreio/csfw/utils/log"
-	"github.com/spf13/viper"
+	"github.com/juju/errgo"
 )
 
+// ErrKeyNotFound gets returned when a path cannot be found in the requested
+// scope and, if bubbling, in the default scope.
+var ErrKeyNotFound = errors.New("Key not found")
+
 // LeftDelim and RightDelim are used withing the core_config_data.value field to allow the replacement
 // of the placeholder in exchange with the current value.
 const (
@@ -55,12 +64,28 @@ type (
 	URLType uint8
 
 	// Reader implements how to receive thread safe a configuration value from a path and or scope.
+	// The Get* functions return the zero value in case of an error. The other
+	// functions return ErrKeyNotFound if a path has not been set or an error if the
+	// value cannot be converted into the requested type.
 	Reader interface {
 		GetString(...ArgFunc) string
 		GetBool(...ArgFunc) bool
 		GetFloat64(o ...ArgFunc) float64
 		GetInt(o ...ArgFunc) int
 		GetDateTime(o ...ArgFunc) time.Time
+		GetStringSlice(o ...ArgFunc) []string
+		GetIntSlice(o ...ArgFunc) []int
+
+		// Lookup returns the raw value and the scope group from where the value
+		// has been retrieved.
+		Lookup(o ...ArgFunc) (interface{}, ScopeGroup, error)
+		String(o ...ArgFunc) (string, error)
+		Bool(o ...ArgFunc) (bool, error)
+		Float64(o ...ArgFunc) (float64, error)
+		Int(o ...ArgFunc) (int, error)
+		DateTime(o ...ArgFunc) (time.Time, error)
+		StringSlice(o ...ArgFunc) ([]string, error)
+		IntSlice(o ...ArgFunc) ([]int, error)
 	}
 
 	// Writer thread safe storing of configuration values under different paths and scopes.
@@ -70,9 +95,54 @@ type (
 
 	// Manager main configuration struct
 	Manager struct {
-		// why is Viper private? Because it can maybe replaced by something else ...
-		v *viper.Viper
+		// snapGen changes with each Write, a Snapshot built in the meantime
+		// won't be cached. First field for the 64-bit alignment of atomic.
+		snapGen uint64
+		// storage contains all written values. Default: in-memory storage.
+		storage Storager
+		// defaults contains the default values of the SectionSlices and has
+		// always the lowest precedence.
+		defaults Storager
+		// overrides from the environment have the highest precedence. Can be nil.
+		overrides *EnvStorage
+		// ps notifies the subscribers after a Write
+		ps *pubSub
+		// sections contains all applied SectionSlices to look up the Field
+		// of a path, e.g. for decoding slices. Access via fields().
+		sections   SectionSlice
+		sectionsMu sync.RWMutex
+		// strict enables the validation of each Write against the sections
+		strict bool
+		// snapshots contains the snapshotCache, see Snapshot(). Reads are
+		// lock-free, snapMu serializes storing, updating and invalidating.
+		snapshots atomic.Value
+		snapMu    sync.Mutex
+		// hierarchy contains a scopeHierarchy, see SetScopeHierarchy()
+		hierarchy atomic.Value
+	}
+
+	// scopeHierarchy wraps the interface because atomic.Value requires
+	// always the same concrete type. ids caches the website IDs of the
+	// stores and groups, see InvalidateScopeHierarchy().
+	scopeHierarchy struct {
+		ScopeHierarchy
+		ids *websiteIDCache
+	}
+
+	// websiteIDCache maps a store or group scope to the ID of its website
+	websiteIDCache struct {
+		mu  sync.RWMutex
+		ids map[scopeKey]int64
+	}
+
+	// scopeKey identifies a store or group
+	scopeKey struct {
+		sg ScopeGroup
+		id int64
 	}
+
+	// ManagerOption option func for NewManager()
+	ManagerOption func(*Manager)
 )
 
 var (
@@ -90,34 +160,81 @@ func init() {
 	DefaultManager = NewManager()
 }
 
+// SetManagerStrict enables the strict mode and adds the SectionSlice. In strict
+// mode Write() rejects unknown paths, scopes which are not allowed by the
+// ScopePerm of a Field and values which do not match the Field. The SectionSlices
+// of ApplyDefaults() will also be used for validation.
+func SetManagerStrict(ss SectionSlice) ManagerOption {
+	return func(m *Manager) {
+		m.strict = true
+		m.sections = append(m.sections, ss...)
+	}
+}
+
+// SetManagerStorage sets the underlying Storager. Use NewLayeredStorage() to
+// combine several backends. Default: in-memory storage.
+func SetManagerStorage(s Storager) ManagerOption {
+	return func(m *Manager) {
+		if s != nil {
+			m.storage = s
+		}
+	}
+}
+
+// SetManagerScopeHierarchy sets the ScopeHierarchy for the fallback from a
+// store or group to its website, see SetScopeHierarchy().
+func SetManagerScopeHierarchy(h ScopeHierarchy) ManagerOption {
+	return func(m *Manager) { m.SetScopeHierarchy(h) }
+}
+
 // NewManager creates the main new configuration for all scopes: default, website and store
-func NewManager() *Manager {
+func NewManager(opts ...ManagerOption) *Manager {
 	s := &Manager{
-		v: viper.New(),
+		storage:  NewMemoryStorage(),
+		defaults: NewMemoryStorage(),
+		ps:       newPubSub(),
+	}
+	s.defaults.Set(newArg(Path(PathCSBaseURL)).scopePath(), CSBaseURL)
+	s.snapshots.Store(snapshotCache{})
+	s.hierarchy.Store(newScopeHierarchy(nil))
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
 	}
-	s.v.Set(newArg(Path(PathCSBaseURL)).scopePath(), CSBaseURL)
 	return s
 }
 
-// ApplyDefaults reads the map and applies the keys and values to the default configuration
+// ApplyDefaults reads the map and applies the keys and values to the default configuration.
+// Defaults have a lower precedence than all values in the Storager.
+// If ss is a SectionSlice its Fields will be used to decode values in e.g. GetStringSlice.
 func (m *Manager) ApplyDefaults(ss Sectioner) *Manager {
+	m.sectionsMu.Lock()
+	switch sst := ss.(type) {
+	case SectionSlice:
+		m.sections = append(m.sections, sst...)
+	case *SectionSlice:
+		m.sections = append(m.sections, *sst...)
+	}
+	m.sectionsMu.Unlock()
 	for k, v := range ss.Defaults() {
 		if log.IsDebug() {
 			log.Debug("Scope=ApplyDefaults", k, v)
 		}
-		m.v.Set(k, v)
+		if err := m.defaults.Set(k, v); err != nil {
+			log.Error("Manager=ApplyDefaults", "err", err, "key", k)
+		}
 	}
+	m.InvalidateSnapshots()
 	return m
 }
 
 // ApplyCoreConfigData reads the table core_config_data into the Manager and overrides
-// existing values. If the column value is NULL entry will be ignored.
+// existing values. If the column value is NULL entry will be ignored. Subscribers
+// will be notified for each applied value. The values skip the BeforeSave() hook
+// of the BackendModel because they have already been saved.
 func (m *Manager) ApplyCoreConfigData(dbrSess dbr.SessionRunner) error {
-	var ccd TableCoreConfigDataSlice
-	rows, err := csdb.LoadSlice(dbrSess, TableCollection, TableIndexCoreConfigData, &ccd)
-	if log.IsDebug() {
-		log.Debug("Manager=ApplyCoreConfigData", "rows", rows)
-	}
+	ccd, err := loadCoreConfigData(dbrSess)
 	if err != nil {
 		return log.Error("Manager=ApplyCoreConfigData", "err", err)
 	}
@@ -125,41 +242,246 @@ func (m *Manager) ApplyCoreConfigData(dbrSess dbr.SessionRunner) error {
 	for _, cd := range ccd {
 		if cd.Value.Valid {
 			// ScopeID(cd.ScopeID) because cd.ScopeID is a struct field and cannot satisfy interface ScopeIDer
-			m.Write(Path(cd.Path), Scope(GetScopeGroup(cd.Scope), ScopeID(cd.ScopeID)))
+			err := m.Write(Path(cd.Path), Scope(GetScopeGroup(cd.Scope), ScopeID(cd.ScopeID)), Value(cd.Value.String), NoBubble(), RawValue())
+			if err != nil && err != ErrKeyOverridden {
+				log.Error("Manager=ApplyCoreConfigData.Write", "err", err, "scope", cd.Scope, "scopeID", cd.ScopeID, "path", cd.Path)
+			}
 		}
 	}
 	return nil
 }
 
+// loadCoreConfigData loads all rows of the table core_config_data.
+func loadCoreConfigData(dbrSess dbr.SessionRunner) (TableCoreConfigDataSlice, error) {
+	var ccd TableCoreConfigDataSlice
+	rows, err := csdb.LoadSlice(dbrSess, TableCollection, TableIndexCoreConfigData, &ccd)
+	if log.IsDebug() {
+		log.Debug("config.loadCoreConfigData", "rows", rows)
+	}
+	return ccd, err
+}
+
 // Write puts a value back into the manager. Example usage:
 // Default Scope: Write(config.Path("currency", "option", "base"), config.Value("USD"))
 // Website Scope: Write(config.Path("currency", "option", "base"), config.Value("EUR"), config.ScopeWebsite(w))
 // Store   Scope: Write(config.Path("currency", "option", "base"), config.ValueReader(resp.Body), config.ScopeStore(s))
+// After writing the path will be updated in the cached Snapshots and the subscribers for
+// the path and scope will be notified. A bubbled value notifies also the
+// subscribers of the default scope.
+// In strict mode the argument will be validated, see SetManagerStrict().
+// The BackendModel of the Field can validate or transform the value before it
+// will be stored. Its error will be returned.
+// Returns ErrKeyOverridden without writing if an environment variable
+// overrides the key, see SetManagerEnvOverrides().
 func (m *Manager) Write(o ...ArgFunc) error {
 	a := newArg(o...)
-	if a.isBubbling() {
+	if err := m.checkWrite(a); err != nil {
+		return err
+	}
+	v, err := beforeSave(m.fields(), m, a)
+	if err != nil {
+		if log.IsDebug() {
+			log.Debug("Manager=Write.BeforeSave", "err", err, "path", a.scopePath(), "val", a.v)
+		}
+		return err
+	}
+	a.v = v
+	if a.isBubbling() && false == a.isDefault() {
 		if log.IsDebug() {
 			log.Debug("Manager=Write", "path", a.scopePathDefault(), "bubble", a.isBubbling(), "val", a.v)
 		}
-		m.v.Set(a.scopePathDefault(), a.v)
+		if err := m.storage.Set(a.scopePathDefault(), a.v); err != nil {
+			return errgo.Mask(err)
+		}
+		m.ps.publish(a.p, ScopeDefaultID, nil)
 	}
 
 	if log.IsDebug() {
 		log.Debug("Manager=Write", "path", a.scopePath(), "val", a.v)
 	}
-	m.v.Set(a.scopePath(), a.v)
+	if err := m.storage.Set(a.scopePath(), a.v); err != nil {
+		return errgo.Mask(err)
+	}
+	m.updateSnapshots(a.p)
+	m.ps.publish(a.p, a.s, a.r)
 
 	return nil
 }
 
-// get generic getter ... not sure if this should be public ...
-func (m *Manager) get(o ...ArgFunc) interface{} {
+// checkWrite validates the argument in strict mode and returns
+// ErrKeyOverridden if the environment overrides the key.
+func (m *Manager) checkWrite(a *arg) error {
+	if m.strict {
+		if err := m.validate(a); err != nil {
+			return err
+		}
+	}
+	if m.isOverridden(a) {
+		if log.IsDebug() {
+			log.Debug("Manager=Write.Overridden", "path", a.scopePath(), "val", a.v)
+		}
+		return ErrKeyOverridden
+	}
+	return nil
+}
+
+// isOverridden checks if the environment overrides the key of the argument or
+// the key of the default scope if the value bubbles.
+func (m *Manager) isOverridden(a *arg) bool {
+	if m.overrides == nil {
+		return false
+	}
+	if _, err := m.overrides.Get(a.scopePath()); err == nil {
+		return true
+	}
+	if a.isBubbling() && false == a.isDefault() {
+		if _, err := m.overrides.Get(a.scopePathDefault()); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SetScopeHierarchy sets the ScopeHierarchy, mostly the store.Manager, which
+// knows the website of a store or group. Without a ScopeHierarchy only a
+// ScopeIDer implementing WebsiteIDer falls back to its website. A nil argument
+// removes the ScopeHierarchy. The website IDs will be cached, see
+// InvalidateScopeHierarchy().
+func (m *Manager) SetScopeHierarchy(h ScopeHierarchy) {
+	m.hierarchy.Store(newScopeHierarchy(h))
+	m.InvalidateSnapshots()
+}
+
+// InvalidateScopeHierarchy removes the cached website IDs of the stores and
+// groups and all cached Snapshots. Call it after a store or group has been
+// moved to another website, e.g. store.Manager does it after a mutation.
+func (m *Manager) InvalidateScopeHierarchy() {
+	h, _ := m.hierarchy.Load().(scopeHierarchy)
+	m.hierarchy.Store(newScopeHierarchy(h.ScopeHierarchy))
+	m.InvalidateSnapshots()
+}
+
+func newScopeHierarchy(h ScopeHierarchy) scopeHierarchy {
+	return scopeHierarchy{
+		ScopeHierarchy: h,
+		ids:            &websiteIDCache{ids: make(map[scopeKey]int64)},
+	}
+}
+
+// websiteID returns the website ID of a store or group scope. ok is false for
+// other scopes or if the website cannot be determined. The IDs of the
+// ScopeHierarchy will be cached.
+func (m *Manager) websiteID(a *arg) (id int64, ok bool) {
+	if a.s != ScopeStoreID && a.s != ScopeGroupID || a.r == nil {
+		return 0, false
+	}
+	if wi, ok := a.r.(WebsiteIDer); ok {
+		return wi.WebsiteID(), true
+	}
+	h, _ := m.hierarchy.Load().(scopeHierarchy)
+	if h.ScopeHierarchy == nil {
+		return 0, false
+	}
+	k := scopeKey{sg: a.s, id: a.scopeIDInt64()}
+	h.ids.mu.RLock()
+	id, ok = h.ids.ids[k]
+	h.ids.mu.RUnlock()
+	if ok {
+		return id, true
+	}
+
+	id, err := h.ParentWebsiteID(k.sg, k.id)
+	if err != nil {
+		if log.IsDebug() {
+			log.Debug("Manager=websiteID", "err", err, "scope", k.sg, "id", k.id)
+		}
+		return 0, false
+	}
+	h.ids.mu.Lock()
+	h.ids.ids[k] = id
+	h.ids.mu.Unlock()
+	return id, true
+}
+
+// Lookup generic getter returns the raw value and the scope group from where the
+// value has been retrieved. If the value cannot be found in the requested scope
+// and bubbling is enabled, a store or group falls back to its website and then
+// to the default scope. Returns ErrKeyNotFound if the path is not set in any of
+// these scopes.
+// The value passes the AfterLoad() hook of the BackendModel of the Field.
+func (m *Manager) Lookup(o ...ArgFunc) (interface{}, ScopeGroup, error) {
 	a := newArg(o...)
-	vs := m.v.Get(a.scopePath()) // vs = value scope
-	if vs == nil && a.isBubbling() {
-		vs = m.v.Get(a.scopePathDefault())
+	vs, sg, err := m.lookup(a)
+	if err != nil {
+		return nil, sg, err
 	}
-	return vs
+	if vs, err = afterLoad(m.fields(), m, a, vs); err != nil {
+		return nil, ScopeAbsentID, errgo.Mask(err)
+	}
+	return vs, sg, nil
+}
+
+// lookup returns the raw value of the scope and if bubbling of the website and
+// default scope.
+func (m *Manager) lookup(a *arg) (interface{}, ScopeGroup, error) {
+	vs, err := m.getKey(a.scopePath()) // vs = value scope
+	switch {
+	case err == nil && a.isDefault():
+		return vs, ScopeDefaultID, nil
+	case err == nil:
+		return vs, a.s, nil
+	case err != ErrKeyNotFound:
+		return nil, ScopeAbsentID, errgo.Mask(err)
+	}
+	if a.isBubbling() && false == a.isDefault() {
+		if wID, ok := m.websiteID(a); ok {
+			vs, err = m.getKey(newArg(Path(a.p), ScopeWebsite(ScopeID(wID))).scopePath())
+			switch {
+			case err == nil:
+				return vs, ScopeWebsiteID, nil
+			case err != ErrKeyNotFound:
+				return nil, ScopeAbsentID, errgo.Mask(err)
+			}
+		}
+		vs, err = m.getKey(a.scopePathDefault())
+		switch {
+		case err == nil:
+			return vs, ScopeDefaultID, nil
+		case err != ErrKeyNotFound:
+			return nil, ScopeAbsentID, errgo.Mask(err)
+		}
+	}
+	return nil, ScopeAbsentID, ErrKeyNotFound
+}
+
+// getKey returns the value of a fully qualified key from the overrides, the
+// Storager or from the defaults.
+func (m *Manager) getKey(key string) (interface{}, error) {
+	if m.overrides != nil {
+		if v, err := m.overrides.Get(key); err != ErrKeyNotFound {
+			return v, err
+		}
+	}
+	v, err := m.storage.Get(key)
+	if err == ErrKeyNotFound {
+		return m.defaults.Get(key)
+	}
+	return v, err
+}
+
+// String returns a string from the manager. Returns ErrKeyNotFound if the path
+// has not been set or a masked error if the value cannot be converted.
+// Example usage:
+// Default value: String(config.Path("general/locale/timezone"))
+// Website value: String(config.Path("general/locale/timezone"), config.ScopeWebsite(w))
+// Store   value: String(config.Path("general/locale/timezone"), config.ScopeStore(s))
+func (m *Manager) String(o ...ArgFunc) (string, error) {
+	vs, _, err := m.Lookup(o...)
+	if err != nil {
+		return "", err
+	}
+	s, err := cast.ToStringE(vs)
+	return s, errgo.Mask(err)
 }
 
 // GetString returns a string from the manager. Example usage:
@@ -167,62 +489,238 @@ func (m *Manager) get(o ...ArgFunc) interface{} {
 // Website value: GetString(config.Path("general/locale/timezone"), config.ScopeWebsite(w))
 // Store   value: GetString(config.Path("general/locale/timezone"), config.ScopeStore(s))
 func (m *Manager) GetString(o ...ArgFunc) string {
-	vs := m.get(o...)
-	if vs == nil {
-		return ""
+	s, _ := m.String(o...)
+	return s
+}
+
+// StringSlice returns a slice of strings from the manager. Strings of a Field with
+// the type TypeMultiselect or of an unknown path will be split by comma, all
+// other strings return a slice with one element. Error behaviour see String.
+func (m *Manager) StringSlice(o ...ArgFunc) ([]string, error) {
+	vs, _, err := m.Lookup(o...)
+	if err != nil {
+		return nil, err
+	}
+	return ToStringSlice(vs, m.isCommaList(newArg(o...).p))
+}
+
+// ToStringSlice decodes a raw value into a slice of strings. A string will be
+// split by comma if commaList is true, e.g. for a Field with the type
+// TypeMultiselect, otherwise it returns a slice with one element. An empty
+// string returns nil.
+func ToStringSlice(v interface{}, commaList bool) ([]string, error) {
+	str, ok := v.(string)
+	if !ok {
+		ss, err := cast.ToStringSliceE(v)
+		return ss, errgo.Mask(err)
 	}
-	return cast.ToString(vs)
+	if str == "" {
+		return nil, nil
+	}
+	if false == commaList {
+		return []string{str}, nil
+	}
+	ss := strings.Split(str, ",")
+	for i, s := range ss {
+		ss[i] = strings.TrimSpace(s)
+	}
+	return ss, nil
 }
 
-// @todo use the backend model of a config value. most/all magento string slices are comma lists.
+// GetStringSlice returns a slice of strings from the manager. Example usage see GetString
+// and for the decoding see StringSlice.
 func (m *Manager) GetStringSlice(o ...ArgFunc) []string {
-	return nil
-	//	return m.v.GetStringSlice(newArg(o...))
+	ss, _ := m.StringSlice(o...)
+	return ss
+}
+
+// IntSlice returns a slice of ints from the manager. Decoding see ToIntSlice.
+// Returns an error if an element is not an integer.
+func (m *Manager) IntSlice(o ...ArgFunc) ([]int, error) {
+	vs, _, err := m.Lookup(o...)
+	if err != nil {
+		return nil, err
+	}
+	return ToIntSlice(vs, m.isCommaList(newArg(o...).p))
+}
+
+// ToIntSlice decodes a raw value into a slice of ints. A string will be
+// decoded like ToStringSlice and each element converted to an int. Returns an
+// error if an element is not an integer.
+func ToIntSlice(v interface{}, commaList bool) ([]int, error) {
+	if _, ok := v.(string); !ok {
+		is, err := cast.ToIntSliceE(v)
+		return is, errgo.Mask(err)
+	}
+	ss, err := ToStringSlice(v, commaList)
+	if err != nil {
+		return nil, err
+	}
+	var is []int
+	for _, s := range ss {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errgo.Mask(err)
+		}
+		is = append(is, i)
+	}
+	return is, nil
+}
+
+// GetIntSlice returns a slice of ints from the manager. Example usage see GetString.
+func (m *Manager) GetIntSlice(o ...ArgFunc) []int {
+	is, _ := m.IntSlice(o...)
+	return is
+}
+
+// isCommaList checks if the Field for the path stores a comma separated list
+// like Magento does for multiselect fields. Returns true if no Field can be
+// found, so only the value of a known Field which is not a multiselect list
+// stays intact.
+func (m *Manager) isCommaList(path string) bool {
+	f, err := m.fields().FindFieldByPath(path)
+	if err != nil {
+		return true
+	}
+	return f.Type != nil && f.Type.Type() == TypeMultiselect
+}
+
+// fields returns the applied SectionSlices. Appending in ApplyDefaults() does
+// not change the returned slice.
+func (m *Manager) fields() SectionSlice {
+	m.sectionsMu.RLock()
+	defer m.sectionsMu.RUnlock()
+	return m.sections[:len(m.sections):len(m.sections)]
+}
+
+// Bool returns a bool from the manager. Error behaviour see String.
+func (m *Manager) Bool(o ...ArgFunc) (bool, error) {
+	vs, _, err := m.Lookup(o...)
+	if err != nil {
+		return false, err
+	}
+	b, err := cast.ToBoolE(vs)
+	return b, errgo.Mask(err)
 }
 
 // GetBool returns bool from the manager. Example usage see GetString.
 func (m *Manager) GetBool(o ...ArgFunc) bool {
-	vs := m.get(o...)
-	if vs == nil {
-		return false
+	b, _ := m.Bool(o...)
+	return b
+}
+
+// Float64 returns a float64 from the manager. Error behaviour see String.
+func (m *Manager) Float64(o ...ArgFunc) (float64, error) {
+	vs, _, err := m.Lookup(o...)
+	if err != nil {
+		return 0.0, err
 	}
-	return cast.ToBool(vs)
+	f, err := cast.ToFloat64E(vs)
+	return f, errgo.Mask(err)
 }
 
 // GetFloat64 returns a float64 from the manager. Example usage see GetString.
 func (m *Manager) GetFloat64(o ...ArgFunc) float64 {
-	vs := m.get(o...)
-	if vs == nil {
-		return 0.0
+	f, _ := m.Float64(o...)
+	return f
+}
+
+// Int returns an int from the manager. Error behaviour see String.
+func (m *Manager) Int(o ...ArgFunc) (int, error) {
+	vs, _, err := m.Lookup(o...)
+	if err != nil {
+		return 0, err
 	}
-	return cast.ToFloat64(vs)
+	i, err := cast.ToIntE(vs)
+	return i, errgo.Mask(err)
 }
 
 // GetInt returns an int from the manager. Example usage see GetString.
 func (m *Manager) GetInt(o ...ArgFunc) int {
-	vs := m.get(o...)
-	if vs == nil {
-		return 0
+	i, _ := m.Int(o...)
+	return i
+}
+
+// DateTime returns a date and time object from the manager. Error behaviour see String.
+func (m *Manager) DateTime(o ...ArgFunc) (time.Time, error) {
+	vs, _, err := m.Lookup(o...)
+	if err != nil {
+		return time.Time{}, err
 	}
-	return cast.ToInt(vs)
+	t, err := cast.ToTimeE(vs)
+	return t, errgo.Mask(err)
 }
 
 // GetDateTime returns a date and time object from the manager. Example usage see GetString.
 func (m *Manager) GetDateTime(o ...ArgFunc) time.Time {
-	vs := m.get(o...)
-	t, err := cast.ToTimeE(vs)
-	if err != nil {
-		log.Error("Manager=GetDateTime", "err", err, "val", vs)
+	t, err := m.DateTime(o...)
+	if err != nil && err != ErrKeyNotFound {
+		log.Error("Manager=GetDateTime", "err", err)
 	}
 	return t
 }
 
-// @todo consider adding other Get* from the viper package
-
 // AllKeys return all keys regardless where they are set
-func (m *Manager) AllKeys() []string { return m.v.AllKeys() }
+func (m *Manager) AllKeys() []string {
+	var overrides Storager
+	if m.overrides != nil {
+		overrides = m.overrides
+	}
+	keys, err := NewLayeredStorage(overrides, m.storage, m.defaults).AllKeys()
+	if err != nil {
+		log.Error("Manager=AllKeys", "err", err)
+	}
+	return keys
+}
+
+// ChildScopes returns the website, group and store scopes below the scope sg
+// and r which have their own value for one of the paths. All other scopes
+// below inherit the values. Stores and groups whose website cannot be
+// determined will be skipped, see SetScopeHierarchy().
+func (m *Manager) ChildScopes(sg ScopeGroup, r ScopeIDer, paths ...string) []ArgFunc {
+	p := newArg(Scope(sg, r))
+	if p.s != ScopeDefaultID && p.s != ScopeWebsiteID {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var scopes []ArgFunc
+	for _, key := range m.AllKeys() {
+		scope, id, path, err := splitKey(key)
+		if err != nil || scope == ScopeRangeDefault || false == isOneOf(path, paths) {
+			continue
+		}
+		sk := scope + PS + strconv.FormatInt(id, 10)
+		if seen[sk] {
+			continue
+		}
+		seen[sk] = true
+
+		c := newArg(Scope(GetScopeGroup(scope), ScopeID(id)))
+		if p.s == ScopeWebsiteID {
+			switch wID, ok := m.websiteID(c); {
+			case c.s == ScopeWebsiteID && id != p.scopeIDInt64():
+				continue
+			case c.s != ScopeWebsiteID && (false == ok || wID != p.scopeIDInt64()):
+				continue
+			}
+		}
+		scopes = append(scopes, Scope(c.s, c.r))
+	}
+	return scopes
+}
+
+// isOneOf checks if s is contained in ss.
+func isOneOf(s string, ss []string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
 
 // IsSet checks if a key is in the config. Does not bubble.
 func (m *Manager) IsSet(o ...ArgFunc) bool {
-	return m.v.IsSet(newArg(o...).scopePath())
+	_, err := m.getKey(newArg(o...).scopePath())
+	return err == nil
 }
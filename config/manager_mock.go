@@ -32,6 +32,9 @@ type MockReader struct {
 	f64 func(path string) float64
 	i   func(path string) int
 	t   func(path string) time.Time
+	ss  func(path string) []string
+	is  func(path string) []int
+	l   func(path string) (interface{}, ScopeGroup, error)
 }
 
 // MockPathScopeDefault creates for testing a fully qualified path for the
@@ -92,6 +95,30 @@ func MockTime(f func(path string) time.Time) mockOptionFunc {
 	}
 }
 
+// MockStringSlice returns a function which can be used in the NewMockReader().
+// Your function returns a string slice from a given path.
+func MockStringSlice(f func(path string) []string) mockOptionFunc {
+	return func(mr *MockReader) {
+		mr.ss = f
+	}
+}
+
+// MockIntSlice returns a function which can be used in the NewMockReader().
+// Your function returns an int slice from a given path.
+func MockIntSlice(f func(path string) []int) mockOptionFunc {
+	return func(mr *MockReader) {
+		mr.is = f
+	}
+}
+
+// MockLookup returns a function which can be used in the NewMockReader().
+// Your function returns the raw value, the scope group and an error from a given path.
+func MockLookup(f func(path string) (interface{}, ScopeGroup, error)) mockOptionFunc {
+	return func(mr *MockReader) {
+		mr.l = f
+	}
+}
+
 // NewMockReader used for testing
 func NewMockReader(opts ...mockOptionFunc) *MockReader {
 	mr := &MockReader{}
@@ -132,3 +159,79 @@ func (sr *MockReader) GetDateTime(opts ...ArgFunc) time.Time {
 	}
 	return sr.t(newArg(opts...).scopePath())
 }
+func (sr *MockReader) GetStringSlice(opts ...ArgFunc) []string {
+	if sr.ss == nil {
+		return nil
+	}
+	return sr.ss(newArg(opts...).scopePath())
+}
+func (sr *MockReader) GetIntSlice(opts ...ArgFunc) []int {
+	if sr.is == nil {
+		return nil
+	}
+	return sr.is(newArg(opts...).scopePath())
+}
+
+// Lookup calls the MockLookup function or returns ErrKeyNotFound.
+func (sr *MockReader) Lookup(opts ...ArgFunc) (interface{}, ScopeGroup, error) {
+	if sr.l == nil {
+		return nil, ScopeAbsentID, ErrKeyNotFound
+	}
+	return sr.l(newArg(opts...).scopePath())
+}
+
+// String returns ErrKeyNotFound if no MockString function has been set.
+func (sr *MockReader) String(opts ...ArgFunc) (string, error) {
+	if sr.s == nil {
+		return "", ErrKeyNotFound
+	}
+	return sr.GetString(opts...), nil
+}
+
+// Bool returns ErrKeyNotFound if no MockBool function has been set.
+func (sr *MockReader) Bool(opts ...ArgFunc) (bool, error) {
+	if sr.b == nil {
+		return false, ErrKeyNotFound
+	}
+	return sr.GetBool(opts...), nil
+}
+
+// Float64 returns ErrKeyNotFound if no MockFloat64 function has been set.
+func (sr *MockReader) Float64(opts ...ArgFunc) (float64, error) {
+	if sr.f64 == nil {
+		return 0.0, ErrKeyNotFound
+	}
+	return sr.GetFloat64(opts...), nil
+}
+
+// Int returns ErrKeyNotFound if no MockInt function has been set.
+func (sr *MockReader) Int(opts ...ArgFunc) (int, error) {
+	if sr.i == nil {
+		return 0, ErrKeyNotFound
+	}
+	return sr.GetInt(opts...), nil
+}
+
+// DateTime returns ErrKeyNotFound if no MockTime function has been set.
+func (sr *MockReader) DateTime(opts ...ArgFunc) (time.Time, error) {
+	if sr.t == nil {
+		return time.Time{}, ErrKeyNotFound
+	}
+	return sr.GetDateTime(opts...), nil
+}
+
+// StringSlice returns ErrKeyNotFound if no MockStringSlice function has been set.
+func (sr *MockReader) StringSlice(opts ...ArgFunc) ([]string, error) {
+	if sr.ss == nil {
+		return nil, ErrKeyNotFound
+	}
+	return sr.GetStringSlice(opts...), nil
+}
+
+// IntSlice returns ErrKeyNotFound if no MockIntSlice function has been set.
+func (sr *MockReader) IntSlice(opts ...ArgFunc) ([]int, error) {
+	if sr.is == nil {
+		return nil, ErrKeyNotFound
+	}
+	return sr.GetIntSlice(opts...), nil
+}
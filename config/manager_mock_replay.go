@@ -0,0 +1,232 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/errgo"
+)
+
+var _ Reader = (*MockReplayReader)(nil)
+
+// MockErrorer reports unexpected paths of the MockReplayReader, mostly a
+// *testing.T.
+type MockErrorer interface {
+	Errorf(format string, args ...interface{})
+}
+
+// MockReplayReader used for testing. It returns preloaded values, keyed by the
+// fully qualified path e.g. stores/1/web/unsecure/base_url, see
+// MockPathScopeStore(), and records each requested path and scope. A value
+// not found in the requested scope falls back to the website of a ScopeIDer
+// implementing WebsiteIDer and to the default scope if bubbling is enabled,
+// like in the Manager. Requesting a path without a value reports
+// an error to the MockErrorer. The conversion of the values equals the Manager.
+type MockReplayReader struct {
+	t  MockErrorer
+	ms Storager
+	m  *Manager
+
+	mu       sync.Mutex
+	requests []string
+	used     map[string]bool
+}
+
+// NewMockReplayReader creates a new empty MockReplayReader. t can be nil then
+// unexpected paths only return ErrKeyNotFound.
+func NewMockReplayReader(t MockErrorer) *MockReplayReader {
+	ms := NewMemoryStorage()
+	return &MockReplayReader{
+		t:    t,
+		ms:   ms,
+		m:    NewManager(SetManagerStorage(ms)),
+		used: make(map[string]bool),
+	}
+}
+
+// Preload adds the values keyed by the fully qualified path. Returns
+// ErrInvalidKey if a key does not contain scope, scope ID and path.
+func (mr *MockReplayReader) Preload(values map[string]interface{}) error {
+	for k, v := range values {
+		if _, _, _, err := splitKey(k); err != nil {
+			return errgo.Newf("%s: %q", err, k)
+		}
+		if err := mr.ms.Set(k, v); err != nil {
+			return errgo.Mask(err)
+		}
+	}
+	return nil
+}
+
+// PreloadJSON adds the values of a JSON object keyed by the fully qualified
+// path, e.g. a fixture file:
+//
+//	{"default/0/web/cookie/cookie_path": "/", "stores/1/general/locale/code": "de_DE"}
+func (mr *MockReplayReader) PreloadJSON(r io.Reader) error {
+	var values map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&values); err != nil {
+		return errgo.Mask(err)
+	}
+	return mr.Preload(values)
+}
+
+// Requests returns all requested fully qualified paths in the order of the
+// requests.
+func (mr *MockReplayReader) Requests() []string {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return append([]string(nil), mr.requests...)
+}
+
+// Unused returns the sorted preloaded paths which have never been returned.
+func (mr *MockReplayReader) Unused() []string {
+	keys, _ := mr.ms.AllKeys()
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	var unused []string
+	for _, k := range keys {
+		if false == mr.used[k] {
+			unused = append(unused, k)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// record stores the requested path and reports it if there is no value. The
+// key of the scope which returns the value will be marked as used.
+func (mr *MockReplayReader) record(o []ArgFunc) {
+	a := newArg(o...)
+	key := a.scopePath()
+	_, sg, err := mr.m.lookup(a)
+
+	var usedKey string
+	switch sg {
+	case ScopeDefaultID:
+		usedKey = a.scopePathDefault()
+	case ScopeWebsiteID:
+		usedKey = key
+		if wID, ok := mr.m.websiteID(a); ok && a.s != ScopeWebsiteID {
+			usedKey = newArg(Path(a.p), ScopeWebsite(ScopeID(wID))).scopePath()
+		}
+	default:
+		usedKey = key
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	mr.requests = append(mr.requests, key)
+	switch {
+	case err == nil:
+		mr.used[usedKey] = true
+	case mr.t != nil:
+		mr.t.Errorf("MockReplayReader: Unexpected path %q: %s", key, err)
+	}
+}
+
+// Lookup returns the raw value and the scope group.
+func (mr *MockReplayReader) Lookup(o ...ArgFunc) (interface{}, ScopeGroup, error) {
+	mr.record(o)
+	return mr.m.Lookup(o...)
+}
+
+// String returns a string, see Manager.String.
+func (mr *MockReplayReader) String(o ...ArgFunc) (string, error) {
+	mr.record(o)
+	return mr.m.String(o...)
+}
+
+// GetString returns a string, see Manager.GetString.
+func (mr *MockReplayReader) GetString(o ...ArgFunc) string {
+	s, _ := mr.String(o...)
+	return s
+}
+
+// Bool returns a bool, see Manager.Bool.
+func (mr *MockReplayReader) Bool(o ...ArgFunc) (bool, error) {
+	mr.record(o)
+	return mr.m.Bool(o...)
+}
+
+// GetBool returns a bool, see Manager.GetBool.
+func (mr *MockReplayReader) GetBool(o ...ArgFunc) bool {
+	b, _ := mr.Bool(o...)
+	return b
+}
+
+// Float64 returns a float64, see Manager.Float64.
+func (mr *MockReplayReader) Float64(o ...ArgFunc) (float64, error) {
+	mr.record(o)
+	return mr.m.Float64(o...)
+}
+
+// GetFloat64 returns a float64, see Manager.GetFloat64.
+func (mr *MockReplayReader) GetFloat64(o ...ArgFunc) float64 {
+	f, _ := mr.Float64(o...)
+	return f
+}
+
+// Int returns an int, see Manager.Int.
+func (mr *MockReplayReader) Int(o ...ArgFunc) (int, error) {
+	mr.record(o)
+	return mr.m.Int(o...)
+}
+
+// GetInt returns an int, see Manager.GetInt.
+func (mr *MockReplayReader) GetInt(o ...ArgFunc) int {
+	i, _ := mr.Int(o...)
+	return i
+}
+
+// DateTime returns a time, see Manager.DateTime.
+func (mr *MockReplayReader) DateTime(o ...ArgFunc) (time.Time, error) {
+	mr.record(o)
+	return mr.m.DateTime(o...)
+}
+
+// GetDateTime returns a time, see Manager.GetDateTime.
+func (mr *MockReplayReader) GetDateTime(o ...ArgFunc) time.Time {
+	t, _ := mr.DateTime(o...)
+	return t
+}
+
+// StringSlice returns a string slice, see Manager.StringSlice.
+func (mr *MockReplayReader) StringSlice(o ...ArgFunc) ([]string, error) {
+	mr.record(o)
+	return mr.m.StringSlice(o...)
+}
+
+// GetStringSlice returns a string slice, see Manager.GetStringSlice.
+func (mr *MockReplayReader) GetStringSlice(o ...ArgFunc) []string {
+	ss, _ := mr.StringSlice(o...)
+	return ss
+}
+
+// IntSlice returns an int slice, see Manager.IntSlice.
+func (mr *MockReplayReader) IntSlice(o ...ArgFunc) ([]int, error) {
+	mr.record(o)
+	return mr.m.IntSlice(o...)
+}
+
+// GetIntSlice returns an int slice, see Manager.GetIntSlice.
+func (mr *MockReplayReader) GetIntSlice(o ...ArgFunc) []int {
+	is, _ := mr.IntSlice(o...)
+	return is
+}
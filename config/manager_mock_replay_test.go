@@ -0,0 +1,98 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// errorRecorder collects the reported errors of the MockReplayReader
+type errorRecorder []string
+
+func (er *errorRecorder) Errorf(format string, args ...interface{}) {
+	*er = append(*er, fmt.Sprintf(format, args...))
+}
+
+func TestMockReplayReader(t *testing.T) {
+	er := &errorRecorder{}
+	mr := config.NewMockReplayReader(er)
+	assert.NoError(t, mr.Preload(map[string]interface{}{
+		config.MockPathScopeDefault(0, "web/cookie/cookie_path"): "/",
+		config.MockPathScopeStore(1, "web/cookie/cookie_path"):   "/de",
+		config.MockPathScopeDefault(0, "web/cookie/unused"):      "x",
+	}))
+	assert.NoError(t, mr.PreloadJSON(strings.NewReader(`{
+		"websites/1/web/cookie/cookie_lifetime": 3600,
+		"default/0/web/cookie/cookie_httponly": true,
+		"default/0/currency/options/allow": ["USD", "EUR"],
+		"default/0/web/cookie/ids": [1, 2]
+	}`)))
+
+	assert.Exactly(t, "/de", mr.GetString(config.Path("web/cookie/cookie_path"), config.ScopeStore(config.ScopeID(1))))
+	assert.Exactly(t, "/", mr.GetString(config.Path("web/cookie/cookie_path"), config.ScopeStore(config.ScopeID(2))))
+	assert.Exactly(t, 3600, mr.GetInt(config.Path("web/cookie/cookie_lifetime"), config.ScopeWebsite(config.ScopeID(1))))
+	assert.True(t, mr.GetBool(config.Path("web/cookie/cookie_httponly")))
+	assert.Exactly(t, []string{"USD", "EUR"}, mr.GetStringSlice(config.Path("currency/options/allow")))
+	assert.Exactly(t, []int{1, 2}, mr.GetIntSlice(config.Path("web/cookie/ids")))
+	assert.Empty(t, *er)
+
+	_, err := mr.String(config.Path("web/cookie/cookie_path"), config.ScopeStore(config.ScopeID(2)), config.NoBubble())
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+	assert.Exactly(t, 0.0, mr.GetFloat64(config.Path("a/b/c")))
+	if assert.Len(t, *er, 2) {
+		assert.Contains(t, (*er)[0], `"stores/2/web/cookie/cookie_path"`)
+		assert.Contains(t, (*er)[1], `"default/0/a/b/c"`)
+	}
+
+	assert.Exactly(t, []string{
+		"stores/1/web/cookie/cookie_path",
+		"stores/2/web/cookie/cookie_path",
+		"websites/1/web/cookie/cookie_lifetime",
+		"default/0/web/cookie/cookie_httponly",
+		"default/0/currency/options/allow",
+		"default/0/web/cookie/ids",
+		"stores/2/web/cookie/cookie_path",
+		"default/0/a/b/c",
+	}, mr.Requests())
+	assert.Exactly(t, []string{"default/0/web/cookie/unused"}, mr.Unused())
+}
+
+func TestMockReplayReaderUsedFallback(t *testing.T) {
+	er := &errorRecorder{}
+	mr := config.NewMockReplayReader(er)
+	assert.NoError(t, mr.Preload(map[string]interface{}{
+		config.MockPathScopeDefault(0, "web/cookie/cookie_path"): "/",
+		"websites/1/web/cookie/cookie_path":                      "/euro",
+		config.MockPathScopeDefault(0, "web/cookie/domain"):      "shop.io",
+	}))
+
+	assert.Exactly(t, "/euro", mr.GetString(config.Path("web/cookie/cookie_path"), config.ScopeStore(storeWebsite{2, 1})))
+	assert.Exactly(t, "shop.io", mr.GetString(config.Path("web/cookie/domain"), config.ScopeStore(storeWebsite{2, 1})))
+	assert.Empty(t, *er)
+	assert.Exactly(t, []string{"default/0/web/cookie/cookie_path"}, mr.Unused(), "only the answering scope is used")
+}
+
+func TestMockReplayReaderPreloadError(t *testing.T) {
+	mr := config.NewMockReplayReader(nil)
+	assert.Error(t, mr.Preload(map[string]interface{}{"web/cookie": 1}))
+	assert.Error(t, mr.PreloadJSON(strings.NewReader(`[1]`)))
+	_, err := mr.Int(config.Path("web/cookie/cookie_lifetime"))
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+}
@@ -15,7 +15,10 @@
 package config_test
 
 import (
+	"errors"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/storage/csdb"
@@ -81,3 +84,218 @@ func TestApplyCoreConfigData(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestManagerTypedGetters(t *testing.T) {
+	m := config.NewManager()
+	assert.NoError(t, m.Write(config.Path("a/b/c"), config.Value("3.14")))
+	assert.NoError(t, m.Write(config.Path("a/b/c"), config.Value("2"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("a/b/d"), config.Value([]int{1})))
+
+	v, sg, err := m.Lookup(config.Path("a/b/c"), config.ScopeStore(config.ScopeID(2)))
+	assert.NoError(t, err)
+	assert.Exactly(t, "3.14", v)
+	assert.Exactly(t, config.ScopeDefaultID, sg)
+
+	v, sg, err = m.Lookup(config.Path("a/b/c"), config.ScopeWebsite(config.ScopeID(1)))
+	assert.NoError(t, err)
+	assert.Exactly(t, "2", v)
+	assert.Exactly(t, config.ScopeWebsiteID, sg)
+
+	_, sg, err = m.Lookup(config.Path("a/b/c"), config.ScopeStore(config.ScopeID(2)), config.NoBubble())
+	assert.EqualError(t, err, config.ErrKeyNotFound.Error())
+	assert.Exactly(t, config.ScopeAbsentID, sg)
+
+	f, err := m.Float64(config.Path("a/b/c"))
+	assert.NoError(t, err)
+	assert.Exactly(t, 3.14, f)
+
+	i, err := m.Int(config.Path("a/b/c"), config.ScopeWebsite(config.ScopeID(1)))
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, i)
+
+	_, err = m.String(config.Path("x/y/z"))
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+
+	_, err = m.Int(config.Path("a/b/d"))
+	assert.Error(t, err)
+	assert.NotEqual(t, config.ErrKeyNotFound, err)
+
+	_, err = m.DateTime(config.Path("a/b/d"))
+	assert.Error(t, err)
+	assert.Exactly(t, time.Time{}, m.GetDateTime(config.Path("a/b/d")))
+}
+
+func TestManagerSlices(t *testing.T) {
+	pkgCfg := config.NewConfiguration(
+		&config.Section{
+			ID: "currency",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "options",
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `currency/options/allow`,
+							ID:      "allow",
+							Type:    config.TypeMultiselect,
+							Default: `USD, EUR`,
+						},
+						&config.Field{
+							// Path: `currency/options/label`,
+							ID:      "label",
+							Type:    config.TypeText,
+							Default: `Dollar,Euro`,
+						},
+						&config.Field{
+							// Path: `currency/options/groups`,
+							ID:      "groups",
+							Type:    config.TypeMultiselect,
+							Default: `1,2,x`,
+						},
+					},
+				},
+			},
+		},
+	)
+	m := config.NewManager()
+	m.ApplyDefaults(pkgCfg)
+	assert.NoError(t, m.Write(config.Path("a/b/c"), config.Value("3,4"), config.ScopeStore(config.ScopeID(1))))
+	assert.NoError(t, m.Write(config.Path("currency/options/groups"), config.Value("3,4"), config.ScopeStore(config.ScopeID(1)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("a/b/d"), config.Value([]string{"x", "y"})))
+	assert.NoError(t, m.Write(config.Path("a/b/e"), config.Value("")))
+
+	assert.Exactly(t, []string{"USD", "EUR"}, m.GetStringSlice(config.Path("currency/options/allow")))
+	assert.Exactly(t, []string{"Dollar,Euro"}, m.GetStringSlice(config.Path("currency/options/label")))
+	assert.Exactly(t, []string{"x", "y"}, m.GetStringSlice(config.Path("a/b/d")))
+	assert.Nil(t, m.GetStringSlice(config.Path("a/b/e")))
+	// a path without a Field will be split
+	assert.Exactly(t, []string{"3", "4"}, m.GetStringSlice(config.Path("a/b/c"), config.ScopeStore(config.ScopeID(1))))
+	assert.Exactly(t, []int{3, 4}, m.GetIntSlice(config.Path("currency/options/groups"), config.ScopeStore(config.ScopeID(1))))
+
+	_, err := m.IntSlice(config.Path("currency/options/groups"))
+	assert.Error(t, err)
+	_, err = m.StringSlice(config.Path("x/y/z"))
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+}
+
+// websiteHierarchy maps store and group IDs to their website IDs
+type websiteHierarchy map[int64]int64
+
+func (wh websiteHierarchy) ParentWebsiteID(sg config.ScopeGroup, id int64) (int64, error) {
+	if w, ok := wh[id]; ok {
+		return w, nil
+	}
+	return 0, errors.New("Not found")
+}
+
+func TestManagerChildScopes(t *testing.T) {
+	m := config.NewManager(config.SetManagerScopeHierarchy(websiteHierarchy{2: 1, 4: 5}))
+	for _, w := range []struct {
+		o config.ArgFunc
+		p string
+		v string
+	}{
+		{config.Scope(config.ScopeDefaultID, nil), "a/b/c", "d"},
+		{config.ScopeWebsite(config.ScopeID(1)), "a/b/c", "w1"},
+		{config.ScopeWebsite(config.ScopeID(3)), "x/y/z", "w3"},
+		{config.ScopeWebsite(config.ScopeID(5)), "a/b/c", "w5"},
+		{config.ScopeStore(config.ScopeID(2)), "a/b/c", "s2"},
+		{config.ScopeStore(config.ScopeID(4)), "a/b/c", "s4"},
+		{config.ScopeStore(config.ScopeID(99)), "a/b/c", "s99"},
+	} {
+		assert.NoError(t, m.Write(config.Path(w.p), config.Value(w.v), w.o, config.NoBubble()))
+	}
+	values := func(scopes []config.ArgFunc) []string {
+		var vs []string
+		for _, s := range scopes {
+			vs = append(vs, m.GetString(config.Path("a/b/c"), s, config.NoBubble()))
+		}
+		sort.Strings(vs)
+		return vs
+	}
+
+	tests := []struct {
+		sg   config.ScopeGroup
+		id   config.ScopeIDer
+		want []string
+	}{
+		{config.ScopeDefaultID, nil, []string{"s2", "s4", "s99", "w1", "w5"}},
+		{config.ScopeWebsiteID, config.ScopeID(1), []string{"s2", "w1"}},
+		{config.ScopeWebsiteID, config.ScopeID(5), []string{"s4", "w5"}},
+		{config.ScopeWebsiteID, config.ScopeID(3), nil},
+		{config.ScopeStoreID, config.ScopeID(2), nil},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.want, values(m.ChildScopes(test.sg, test.id, "a/b/c")), "Index %d", i)
+	}
+	assert.Len(t, m.ChildScopes(config.ScopeDefaultID, nil, "x/y/z"), 1)
+}
+
+func TestManagerWebsiteFallback(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(htmlTestSections)
+	assert.NoError(t, m.Write(config.Path("web/cookie/cookie_path"), config.Value("/website"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("web/cookie/start"), config.Value("8,0,0"), config.ScopeStore(config.ScopeID(2)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("web/cookie/hidden"), config.Value("g"), config.ScopeStoreGroup(config.ScopeID(3)), config.NoBubble()))
+
+	lookup := func(o ...config.ArgFunc) (interface{}, config.ScopeGroup) {
+		v, sg, err := m.Lookup(append(o, config.Path("web/cookie/cookie_path"))...)
+		assert.NoError(t, err)
+		return v, sg
+	}
+
+	// without a ScopeHierarchy only the default scope
+	v, sg := lookup(config.ScopeStore(config.ScopeID(2)))
+	assert.Exactly(t, "/", v)
+	assert.Exactly(t, config.ScopeDefaultID, sg)
+
+	// ScopeIDer implements WebsiteIDer
+	v, sg = lookup(config.ScopeStore(storeWebsite{2, 1}))
+	assert.Exactly(t, "/website", v)
+	assert.Exactly(t, config.ScopeWebsiteID, sg)
+
+	wh := websiteHierarchy{2: 1, 3: 1, 4: 5}
+	m.SetScopeHierarchy(wh)
+	tests := []struct {
+		o       config.ArgFunc
+		wantVal interface{}
+		wantSG  config.ScopeGroup
+	}{
+		{config.ScopeStore(config.ScopeID(2)), "/website", config.ScopeWebsiteID},
+		{config.ScopeStoreGroup(config.ScopeID(3)), "/website", config.ScopeWebsiteID},
+		{config.ScopeStore(config.ScopeID(4)), "/", config.ScopeDefaultID},
+		{config.ScopeStore(config.ScopeID(99)), "/", config.ScopeDefaultID},
+	}
+	for _, test := range tests {
+		v, sg := lookup(test.o)
+		assert.Exactly(t, test.wantVal, v)
+		assert.Exactly(t, test.wantSG, sg)
+	}
+
+	_, _, err := m.Lookup(config.Path("web/cookie/cookie_path"), config.ScopeStore(config.ScopeID(2)), config.NoBubble())
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+	assert.Exactly(t, "8,0,0", m.GetString(config.Path("web/cookie/start"), config.ScopeStore(config.ScopeID(2))))
+	assert.Exactly(t, "g", m.GetString(config.Path("web/cookie/hidden"), config.ScopeStoreGroup(config.ScopeID(3))))
+
+	s := m.Snapshot(config.ScopeGroupID, config.ScopeID(3))
+	assert.Exactly(t, "/website", s.GetString("web/cookie/cookie_path"))
+	assert.Exactly(t, "g", s.GetString("web/cookie/hidden"))
+	assert.Exactly(t, "/website", m.Snapshot(config.ScopeStoreID, config.ScopeID(2)).GetString("web/cookie/cookie_path"))
+
+	// the website IDs are cached until the hierarchy will be invalidated
+	wh[2] = 5
+	v, sg = lookup(config.ScopeStore(config.ScopeID(2)))
+	assert.Exactly(t, "/website", v)
+	m.InvalidateScopeHierarchy()
+	v, sg = lookup(config.ScopeStore(config.ScopeID(2)))
+	assert.Exactly(t, "/", v)
+	assert.Exactly(t, config.ScopeDefaultID, sg)
+	assert.Exactly(t, "/", m.Snapshot(config.ScopeStoreID, config.ScopeID(2)).GetString("web/cookie/cookie_path"))
+	wh[2] = 1
+	m.InvalidateScopeHierarchy()
+
+	m.SetScopeHierarchy(nil)
+	v, sg = lookup(config.ScopeStore(config.ScopeID(2)))
+	assert.Exactly(t, "/", v)
+	assert.Exactly(t, config.ScopeDefaultID, sg)
+	assert.Exactly(t, "/", m.Snapshot(config.ScopeStoreID, config.ScopeID(2)).GetString("web/cookie/cookie_path"))
+}
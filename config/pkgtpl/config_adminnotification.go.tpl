@@ -25,7 +25,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      false,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
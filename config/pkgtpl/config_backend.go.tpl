@@ -379,7 +379,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeWebsite, config.IDScopeStore),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -393,7 +393,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeWebsite, config.IDScopeStore),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -416,7 +416,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -430,7 +430,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      false,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -452,8 +452,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.ScopePermAll,
 						Default:      nil,
-						BackendModel: nil, // Magento\Config\Model\Config\Backend\Translate
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Config\Model\Config\Backend\Translate
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -466,8 +466,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
-						BackendModel: nil, // Magento\Config\Model\Config\Backend\Translate
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Config\Model\Config\Backend\Translate
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -490,7 +490,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -504,7 +504,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -518,7 +518,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -541,7 +541,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -555,7 +555,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -601,7 +601,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -631,7 +631,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Directory\Model\Config\Source\Country
+						SourceModel:  directory.NewSourceCountry(), // Magento\Directory\Model\Config\Source\Country
 					},
 
 					&config.Field{
@@ -645,7 +645,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Directory\Model\Config\Source\Country
+						SourceModel:  directory.NewSourceCountry(), // Magento\Directory\Model\Config\Source\Country
 					},
 
 					&config.Field{
@@ -659,7 +659,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Directory\Model\Config\Source\Country
+						SourceModel:  directory.NewSourceCountry(), // Magento\Directory\Model\Config\Source\Country
 					},
 				},
 			},
@@ -681,8 +681,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.NewScopePerm(config.IDScopeDefault, config.IDScopeWebsite),
 						Default:      nil,
-						BackendModel: nil, // Magento\Config\Model\Config\Backend\Locale\Timezone
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Locale\Timezone
+						BackendModel: nil,                           // Magento\Config\Model\Config\Backend\Locale\Timezone
+						SourceModel:  directory.NewSourceTimezone(), // Magento\Config\Model\Config\Source\Locale\Timezone
 					},
 
 					&config.Field{
@@ -696,7 +696,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Locale
+						SourceModel:  directory.NewSourceLocale(), // Magento\Config\Model\Config\Source\Locale
 					},
 
 					&config.Field{
@@ -748,7 +748,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault, config.IDScopeWebsite),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Directory\Model\Config\Source\Country
+						SourceModel:  directory.NewSourceCountry(), // Magento\Directory\Model\Config\Source\Country
 					},
 
 					&config.Field{
@@ -862,7 +862,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1021,8 +1021,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
-						BackendModel: nil, // Magento\Config\Model\Config\Backend\Admin\Usecustom
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Config\Model\Config\Backend\Admin\Usecustom
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1049,8 +1049,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
-						BackendModel: nil, // Magento\Config\Model\Config\Backend\Admin\Custompath
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Config\Model\Config\Backend\Admin\Custompath
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1086,8 +1086,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
-						BackendModel: nil, // Magento\Config\Model\Config\Backend\Admin\Usesecretkey
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Config\Model\Config\Backend\Admin\Usesecretkey
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1101,7 +1101,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1138,7 +1138,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -1214,7 +1214,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1228,7 +1228,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1242,7 +1242,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1256,7 +1256,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -1270,7 +1270,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault, config.IDScopeWebsite),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
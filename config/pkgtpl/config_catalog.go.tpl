@@ -159,8 +159,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      false,
-						BackendModel: nil, // Magento\Catalog\Model\Indexer\Category\Flat\System\Config\Mode
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Catalog\Model\Indexer\Category\Flat\System\Config\Mode
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -173,8 +173,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
-						BackendModel: nil, // Magento\Catalog\Model\Indexer\Product\Flat\System\Config\Mode
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Catalog\Model\Indexer\Product\Flat\System\Config\Mode
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -202,7 +202,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -216,7 +216,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      true,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -276,7 +276,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      false,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -290,7 +290,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      false,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -359,7 +359,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 
 					&config.Field{
@@ -503,7 +503,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.NewScopePerm(config.IDScopeDefault),
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -533,7 +533,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Enabledisable
+						SourceModel:  config.NewSourceEnableDisable(), // Magento\Config\Model\Config\Source\Enabledisable
 					},
 
 					&config.Field{
@@ -547,7 +547,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Enabledisable
+						SourceModel:  config.NewSourceEnableDisable(), // Magento\Config\Model\Config\Source\Enabledisable
 					},
 
 					&config.Field{
@@ -561,7 +561,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      nil,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Enabledisable
+						SourceModel:  config.NewSourceEnableDisable(), // Magento\Config\Model\Config\Source\Enabledisable
 					},
 				},
 			},
@@ -25,7 +25,7 @@ var PackageConfiguration = config.NewConfiguration(
 						Scope:        config.ScopePermAll,
 						Default:      true,
 						BackendModel: nil,
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Enabledisable
+						SourceModel:  config.NewSourceEnableDisable(), // Magento\Config\Model\Config\Source\Enabledisable
 					},
 				},
 			},
@@ -24,8 +24,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.ScopePermAll,
 						Default:      true,
-						BackendModel: nil, // Magento\Contact\Model\System\Config\Backend\Links
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+						BackendModel: nil,                     // Magento\Contact\Model\System\Config\Backend\Links
+						SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 					},
 				},
 			},
@@ -24,8 +24,8 @@ var PackageConfiguration = config.NewConfiguration(
 						Visible:      config.VisibleYes,
 						Scope:        config.ScopePermAll,
 						Default:      nil,
-						BackendModel: nil, // Magento\Rss\Model\System\Config\Backend\Links
-						SourceModel:  nil, // Magento\Config\Model\Config\Source\Enabledisable
+						BackendModel: nil,                             // Magento\Rss\Model\System\Config\Backend\Links
+						SourceModel:  config.NewSourceEnableDisable(), // Magento\Config\Model\Config\Source\Enabledisable
 					},
 				},
 			},
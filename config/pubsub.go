@@ -0,0 +1,145 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/csfw/utils/log"
+)
+
+// ErrSubscriptionNotFound gets returned when a subscription ID is unknown.
+var ErrSubscriptionNotFound = errors.New("Subscription not found")
+
+// ErrSubscriberNil gets returned when a nil MessageReceiver should be subscribed.
+var ErrSubscriberNil = errors.New("MessageReceiver is nil")
+
+type (
+	// MessageReceiver allows you to listen to write actions. The order of calling
+	// each subscriber is undefined. A returned error gets logged and does not
+	// stop the delivery to other subscribers.
+	MessageReceiver interface {
+		// MessageConfig gets called after a value has been written. Path is the
+		// full three level path e.g. web/secure/base_url, sg the scope group
+		// of the written value and s the ScopeIDer, which is nil for the default scope.
+		MessageConfig(path string, sg ScopeGroup, s ScopeIDer) error
+	}
+
+	// subscription contains the filter for a MessageReceiver
+	subscription struct {
+		path string
+		sg   ScopeGroup
+		// r filters the scope ID, nil receives all IDs of the ScopeGroup.
+		r  ScopeIDer
+		mr MessageReceiver
+	}
+
+	// pubSub handles the subscribers and the publishing of changed paths.
+	pubSub struct {
+		mu     sync.RWMutex
+		subs   map[int]subscription
+		nextID int
+	}
+)
+
+func newPubSub() *pubSub {
+	return &pubSub{
+		subs: make(map[int]subscription),
+	}
+}
+
+// Subscribe adds a MessageReceiver which gets notified when a value under the path
+// prefix has been written. An empty path receives all changes. The prefix
+// web/secure matches web/secure/base_url but not web/secure_xxx/base_url.
+// ScopeGroup filters the scope; ScopeAbsentID receives the changes of all scopes.
+// A non nil ScopeIDer filters additionally the ID of the website, group or store.
+// Returns a unique subscription ID which can be used to Unsubscribe().
+func (m *Manager) Subscribe(path string, sg ScopeGroup, r ScopeIDer, mr MessageReceiver) (subscriptionID int, err error) {
+	if mr == nil {
+		return 0, ErrSubscriberNil
+	}
+	m.ps.mu.Lock()
+	defer m.ps.mu.Unlock()
+	m.ps.nextID++
+	m.ps.subs[m.ps.nextID] = subscription{
+		path: strings.Trim(path, PS),
+		sg:   sg,
+		r:    r,
+		mr:   mr,
+	}
+	return m.ps.nextID, nil
+}
+
+// Unsubscribe removes a subscriber with a specific ID.
+func (m *Manager) Unsubscribe(subscriptionID int) error {
+	m.ps.mu.Lock()
+	defer m.ps.mu.Unlock()
+	if _, ok := m.ps.subs[subscriptionID]; !ok {
+		return ErrSubscriptionNotFound
+	}
+	delete(m.ps.subs, subscriptionID)
+	return nil
+}
+
+// publish sends the path and the scope to all matching subscribers. The
+// subscribers will be called without holding the lock, so they can subscribe,
+// unsubscribe or write themselves.
+func (ps *pubSub) publish(path string, sg ScopeGroup, s ScopeIDer) {
+	if path == "" {
+		return
+	}
+	if sg == ScopeAbsentID {
+		sg = ScopeDefaultID
+	}
+	ps.mu.RLock()
+	subs := make(map[int]MessageReceiver)
+	for id, sub := range ps.subs {
+		if sub.matches(path, sg, s) {
+			subs[id] = sub.mr
+		}
+	}
+	ps.mu.RUnlock()
+
+	for id, mr := range subs {
+		if err := mr.MessageConfig(path, sg, s); err != nil {
+			log.Error("config.pubSub.publish", "err", err, "subscriptionID", id, "path", path, "scope", sg)
+		}
+	}
+}
+
+// matches checks if the path is equal or a child of the subscription path and
+// if the scope group and the scope ID are allowed.
+func (sub subscription) matches(path string, sg ScopeGroup, s ScopeIDer) bool {
+	if sub.sg != ScopeAbsentID && sub.sg != sg {
+		return false
+	}
+	if sub.r != nil && sub.r.ScopeID() != scopeIDOf(s) {
+		return false
+	}
+	if sub.path == "" || sub.path == path {
+		return true
+	}
+	return strings.HasPrefix(path, sub.path+PS)
+}
+
+// scopeIDOf returns the ID of s or 0 for the default scope.
+func scopeIDOf(s ScopeIDer) int64 {
+	if s == nil {
+		return 0
+	}
+	return s.ScopeID()
+}
@@ -0,0 +1,114 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSubscriber struct {
+	paths  []string
+	scopes []config.ScopeGroup
+	err    error
+}
+
+func (ts *testSubscriber) MessageConfig(path string, sg config.ScopeGroup, s config.ScopeIDer) error {
+	ts.paths = append(ts.paths, path)
+	ts.scopes = append(ts.scopes, sg)
+	return ts.err
+}
+
+func TestPubSubSubscribe(t *testing.T) {
+	m := config.NewManager()
+
+	all := &testSubscriber{}
+	web := &testSubscriber{}
+	store := &testSubscriber{err: errors.New("Should only be logged")}
+
+	_, err := m.Subscribe("", config.ScopeAbsentID, nil, all)
+	assert.NoError(t, err)
+	webID, err := m.Subscribe("web/secure", config.ScopeAbsentID, nil, web)
+	assert.NoError(t, err)
+	_, err = m.Subscribe("web", config.ScopeStoreID, nil, store)
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("https://corestore.io")))
+	assert.NoError(t, m.Write(config.Path("web/secure_xxx/base_url"), config.Value("https://corestore.io")))
+	assert.NoError(t, m.Write(config.Path("web/secure/offloader_header"), config.Value("SSL"), config.ScopeStore(config.ScopeID(3))))
+
+	assert.Exactly(t, []string{"web/secure/base_url", "web/secure_xxx/base_url", "web/secure/offloader_header", "web/secure/offloader_header"}, all.paths)
+	assert.Exactly(t, []config.ScopeGroup{config.ScopeDefaultID, config.ScopeDefaultID, config.ScopeDefaultID, config.ScopeStoreID}, all.scopes)
+	assert.Exactly(t, []string{"web/secure/base_url", "web/secure/offloader_header", "web/secure/offloader_header"}, web.paths)
+	assert.Exactly(t, []string{"web/secure/offloader_header"}, store.paths)
+
+	assert.NoError(t, m.Unsubscribe(webID))
+	assert.EqualError(t, m.Unsubscribe(webID), config.ErrSubscriptionNotFound.Error())
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("https://corestore.io"), config.NoBubble()))
+	assert.Len(t, web.paths, 3)
+	assert.Len(t, all.paths, 5)
+
+	_, err = m.Subscribe("web", config.ScopeAbsentID, nil, nil)
+	assert.EqualError(t, err, config.ErrSubscriberNil.Error())
+}
+
+func TestPubSubScopeID(t *testing.T) {
+	m := config.NewManager()
+
+	store3 := &testSubscriber{}
+	website1 := &testSubscriber{}
+	_, err := m.Subscribe("web", config.ScopeStoreID, config.ScopeID(3), store3)
+	assert.NoError(t, err)
+	_, err = m.Subscribe("web", config.ScopeWebsiteID, config.ScopeID(1), website1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("a"), config.ScopeStore(config.ScopeID(3)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("b"), config.ScopeStore(config.ScopeID(4)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("c"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("d"), config.ScopeWebsite(config.ScopeID(2)), config.NoBubble()))
+
+	assert.Exactly(t, []config.ScopeGroup{config.ScopeStoreID}, store3.scopes)
+	assert.Exactly(t, []config.ScopeGroup{config.ScopeWebsiteID}, website1.scopes)
+}
+
+// reentrantSubscriber unsubscribes and writes during the notification.
+type reentrantSubscriber struct {
+	m     *config.Manager
+	id    int
+	calls int
+}
+
+func (rs *reentrantSubscriber) MessageConfig(path string, sg config.ScopeGroup, s config.ScopeIDer) error {
+	rs.calls++
+	if err := rs.m.Unsubscribe(rs.id); err != nil {
+		return err
+	}
+	return rs.m.Write(config.Path("web/unsecure/base_url"), config.Value("http://corestore.io"))
+}
+
+func TestPubSubReentrant(t *testing.T) {
+	m := config.NewManager()
+	rs := &reentrantSubscriber{m: m}
+	var err error
+	rs.id, err = m.Subscribe("web", config.ScopeAbsentID, nil, rs)
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("https://corestore.io")))
+	assert.Exactly(t, 1, rs.calls)
+	assert.Exactly(t, "http://corestore.io", m.GetString(config.Path("web/unsecure/base_url")))
+}
@@ -0,0 +1,181 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// Reloader polls the table core_config_data periodically and writes only the
+// changed values into the Manager. The Manager then notifies its subscribers
+// for each changed path. A deleted row or a value which became NULL will be
+// written as nil so that the getters fall back to the default scope. A value
+// whose Write fails will be retried with the next Reload().
+type Reloader struct {
+	m        *Manager
+	dbrSess  dbr.SessionRunner
+	interval time.Duration
+
+	mu sync.Mutex
+	// last contains the values of the previous Reload() with the
+	// scopePath as key. nil before the first Reload().
+	last map[string]TableCoreConfigData
+	stop chan struct{}
+}
+
+// NewReloader creates a new Reloader for the Manager. Interval must be greater
+// zero when calling Start().
+func NewReloader(m *Manager, dbrSess dbr.SessionRunner, interval time.Duration) *Reloader {
+	return &Reloader{
+		m:        m,
+		dbrSess:  dbrSess,
+		interval: interval,
+	}
+}
+
+// Reload loads the table core_config_data and applies the differences to the
+// previous run. The first run compares the rows against the current values
+// of the Manager. Returns the amount of changed paths.
+func (r *Reloader) Reload() (int, error) {
+	ccd, err := loadCoreConfigData(r.dbrSess)
+	if err != nil {
+		return 0, errgo.Mask(err)
+	}
+	return r.apply(ccd), nil
+}
+
+// reloadWrite contains a changed or a deleted row for the Manager.
+type reloadWrite struct {
+	sp      string
+	cd      TableCoreConfigData
+	deleted bool
+}
+
+// apply writes the changed values of ccd into the Manager. The changes will be
+// computed under the lock but written without it, so that subscribers can call
+// the Reloader. A row whose Write fails will be retried with the next run.
+func (r *Reloader) apply(ccd TableCoreConfigDataSlice) int {
+	r.mu.Lock()
+	last := r.last
+	current := make(map[string]TableCoreConfigData, len(ccd))
+	var writes []reloadWrite
+	for _, cd := range ccd {
+		if cd == nil {
+			continue
+		}
+		sp := newArg(Path(cd.Path), Scope(GetScopeGroup(cd.Scope), ScopeID(cd.ScopeID))).scopePath()
+		current[sp] = *cd
+		if false == r.isEqual(sp, cd) {
+			writes = append(writes, reloadWrite{sp: sp, cd: *cd})
+		}
+	}
+	for sp, cd := range last {
+		if _, ok := current[sp]; !ok {
+			writes = append(writes, reloadWrite{sp: sp, cd: cd, deleted: true})
+		}
+	}
+	r.mu.Unlock()
+
+	changed := 0
+	for _, w := range writes {
+		var v interface{}
+		if w.cd.Value.Valid && false == w.deleted {
+			v = w.cd.Value.String
+		}
+		switch err := r.m.Write(Path(w.cd.Path), Scope(GetScopeGroup(w.cd.Scope), ScopeID(w.cd.ScopeID)), NoBubble(), RawValue(), Value(v)); {
+		case err == ErrKeyOverridden:
+			// the environment wins
+		case err != nil:
+			log.Error("config.Reloader.apply.Write", "err", err, "path", w.sp)
+			// restore the previous state to detect the change again
+			if prev, ok := last[w.sp]; ok {
+				current[w.sp] = prev
+			} else {
+				delete(current, w.sp)
+			}
+		default:
+			changed++
+		}
+	}
+
+	if log.IsDebug() {
+		log.Debug("config.Reloader.apply", "rows", len(ccd), "changed", changed)
+	}
+	r.mu.Lock()
+	r.last = current
+	r.mu.Unlock()
+	return changed
+}
+
+// isEqual compares the row with the previous run or, if there is none, with
+// the value in the Manager.
+func (r *Reloader) isEqual(sp string, cd *TableCoreConfigData) bool {
+	if r.last != nil {
+		prev, ok := r.last[sp]
+		return ok && prev.Value.Valid == cd.Value.Valid && prev.Value.String == cd.Value.String
+	}
+	v, err := r.m.getKey(sp)
+	if err != nil && err != ErrKeyNotFound {
+		return false
+	}
+	mv, err := toDBValue(v)
+	if err != nil {
+		return false
+	}
+	return mv.Valid == cd.Value.Valid && mv.String == cd.Value.String
+}
+
+// Start runs Reload() in the background every interval until Stop() gets called.
+// Errors will be logged.
+func (r *Reloader) Start() {
+	r.mu.Lock()
+	if r.stop != nil {
+		r.mu.Unlock()
+		return
+	}
+	r.stop = make(chan struct{})
+	stop := r.stop
+	r.mu.Unlock()
+
+	go func() {
+		t := time.NewTicker(r.interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				if _, err := r.Reload(); err != nil {
+					log.Error("config.Reloader.Start.Reload", "err", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop terminates the background reloading.
+func (r *Reloader) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
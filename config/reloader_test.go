@@ -0,0 +1,116 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/stretchr/testify/assert"
+)
+
+type reloaderSubscriber []string
+
+func (rs *reloaderSubscriber) MessageConfig(path string, sg ScopeGroup, s ScopeIDer) error {
+	*rs = append(*rs, path)
+	return nil
+}
+
+func reloaderRow(scope string, id int64, path, val string, valid bool) *TableCoreConfigData {
+	return &TableCoreConfigData{
+		Scope:   scope,
+		ScopeID: id,
+		Path:    path,
+		Value:   dbr.NullString{NullString: sql.NullString{String: val, Valid: valid}},
+	}
+}
+
+func TestReloaderApply(t *testing.T) {
+	m := NewManager()
+	m.Write(Path("a/b/c"), Value(1))
+
+	var rs reloaderSubscriber
+	_, err := m.Subscribe("", ScopeAbsentID, nil, &rs)
+	assert.NoError(t, err)
+
+	r := NewReloader(m, nil, 0)
+
+	// a/b/c in default scope is equal to the value in the Manager
+	assert.Exactly(t, 1, r.apply(TableCoreConfigDataSlice{
+		reloaderRow(ScopeRangeDefault, 0, "a/b/c", "1", true),
+		reloaderRow(ScopeRangeStores, 2, "a/b/c", "2", true),
+	}))
+	assert.Exactly(t, reloaderSubscriber{"a/b/c"}, rs)
+	assert.Exactly(t, 2, m.GetInt(Path("a/b/c"), ScopeStore(ScopeID(2))))
+
+	rs = rs[:0]
+	assert.Exactly(t, 0, r.apply(TableCoreConfigDataSlice{
+		reloaderRow(ScopeRangeDefault, 0, "a/b/c", "1", true),
+		reloaderRow(ScopeRangeStores, 2, "a/b/c", "2", true),
+	}))
+	assert.Len(t, rs, 0)
+
+	// store row deleted and new website row
+	assert.Exactly(t, 2, r.apply(TableCoreConfigDataSlice{
+		reloaderRow(ScopeRangeDefault, 0, "a/b/c", "1", true),
+		reloaderRow(ScopeRangeWebsites, 1, "d/e/f", "x", true),
+	}))
+	assert.Exactly(t, reloaderSubscriber{"d/e/f", "a/b/c"}, rs)
+	assert.Exactly(t, 1, m.GetInt(Path("a/b/c"), ScopeStore(ScopeID(2))))
+	assert.Exactly(t, "x", m.GetString(Path("d/e/f"), ScopeWebsite(ScopeID(1))))
+}
+
+func TestReloaderApplyRetry(t *testing.T) {
+	ss := SectionSlice{
+		&Section{ID: "a", Groups: GroupSlice{&Group{ID: "b", Fields: FieldSlice{&Field{ID: "c"}}}}},
+	}
+	m := NewManager(SetManagerStrict(ss))
+	r := NewReloader(m, nil, 0)
+
+	rows := TableCoreConfigDataSlice{
+		reloaderRow(ScopeRangeDefault, 0, "a/b/c", "1", true),
+		reloaderRow(ScopeRangeDefault, 0, "d/e/f", "x", true),
+	}
+	// d/e/f is unknown in strict mode
+	assert.Exactly(t, 1, r.apply(rows))
+	assert.False(t, m.IsSet(Path("d/e/f")))
+
+	m.ApplyDefaults(SectionSlice{
+		&Section{ID: "d", Groups: GroupSlice{&Group{ID: "e", Fields: FieldSlice{&Field{ID: "f"}}}}},
+	})
+	assert.Exactly(t, 1, r.apply(rows))
+	assert.Exactly(t, "x", m.GetString(Path("d/e/f")))
+}
+
+// reloaderStopper stops the Reloader when it receives a message
+type reloaderStopper struct{ r *Reloader }
+
+func (rs reloaderStopper) MessageConfig(path string, sg ScopeGroup, s ScopeIDer) error {
+	rs.r.Stop()
+	return nil
+}
+
+func TestReloaderApplySubscriber(t *testing.T) {
+	m := NewManager()
+	r := NewReloader(m, nil, 0)
+	_, err := m.Subscribe("a/b/c", ScopeAbsentID, nil, reloaderStopper{r})
+	assert.NoError(t, err)
+
+	// would deadlock if the subscriber gets notified under the lock
+	assert.Exactly(t, 1, r.apply(TableCoreConfigDataSlice{
+		reloaderRow(ScopeRangeDefault, 0, "a/b/c", "1", true),
+	}))
+}
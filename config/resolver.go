@@ -0,0 +1,171 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// ErrPlaceholderCycle gets returned when a placeholder references itself directly
+// or via other placeholders.
+var ErrPlaceholderCycle = errors.New("Placeholder cycle detected")
+
+type (
+	// PlaceholderProvider returns the value for a registered placeholder name.
+	// The arguments contain the scope of the value which gets expanded. The
+	// returned value will be expanded again.
+	PlaceholderProvider func(r Reader, o ...ArgFunc) (string, error)
+
+	// Resolver expands placeholders between LeftDelim and RightDelim in
+	// configuration values. A placeholder containing a path separator e.g.
+	// {{web/unsecure/base_url}} will be replaced with the value of that path
+	// in the same scope. All other placeholders must be registered otherwise they
+	// will not be replaced.
+	Resolver struct {
+		r         Reader
+		mu        sync.RWMutex
+		providers map[string]PlaceholderProvider
+	}
+)
+
+// NewResolver creates a new Resolver which reads the values from r.
+func NewResolver(r Reader) *Resolver {
+	return &Resolver{
+		r:         r,
+		providers: make(map[string]PlaceholderProvider),
+	}
+}
+
+// Register adds a PlaceholderProvider for a name, e.g. base_url without the
+// delimiters. A nil provider removes the name. A registered name has precedence
+// over a path.
+func (rs *Resolver) Register(name string, p PlaceholderProvider) *Resolver {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if p == nil {
+		delete(rs.providers, name)
+		return rs
+	}
+	rs.providers[name] = p
+	return rs
+}
+
+// Clone returns a copy with the same Reader and providers. Registering a
+// provider in the copy does not change rs, e.g. to replace a placeholder for
+// one request only.
+func (rs *Resolver) Clone() *Resolver {
+	return rs.WithReader(rs.r)
+}
+
+// WithReader returns a copy with all registered providers which reads the
+// values from r.
+func (rs *Resolver) WithReader(r Reader) *Resolver {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	c := NewResolver(r)
+	for name, p := range rs.providers {
+		c.providers[name] = p
+	}
+	return c
+}
+
+// String reads a string value and expands all placeholders. Errors of the
+// Reader will be returned, see Reader.String().
+func (rs *Resolver) String(o ...ArgFunc) (string, error) {
+	val, err := rs.r.String(o...)
+	if err != nil {
+		return "", err
+	}
+	return rs.expand(val, []string{newArg(o...).p}, o)
+}
+
+// Expand replaces all placeholders in value. The arguments define the scope
+// in which referenced paths will be looked up.
+func (rs *Resolver) Expand(value string, o ...ArgFunc) (string, error) {
+	return rs.expand(value, nil, o)
+}
+
+// expand replaces the placeholders in value. The stack contains all names and
+// paths of the current expansion to detect cycles.
+func (rs *Resolver) expand(value string, stack []string, o []ArgFunc) (string, error) {
+	if false == strings.Contains(value, LeftDelim) {
+		return value, nil
+	}
+	var buf bytes.Buffer
+	for {
+		l := strings.Index(value, LeftDelim)
+		if l < 0 {
+			break
+		}
+		r := strings.Index(value[l+len(LeftDelim):], RightDelim)
+		if r < 0 {
+			break
+		}
+		end := l + len(LeftDelim) + r + len(RightDelim)
+		name := strings.TrimSpace(value[l+len(LeftDelim) : l+len(LeftDelim)+r])
+
+		buf.WriteString(value[:l])
+		val, ok, err := rs.placeholder(name, stack, o)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			val = value[l:end]
+		}
+		buf.WriteString(val)
+		value = value[end:]
+	}
+	buf.WriteString(value)
+	return buf.String(), nil
+}
+
+// placeholder returns the expanded value of a placeholder. ok is false if the
+// placeholder is neither registered nor a path.
+func (rs *Resolver) placeholder(name string, stack []string, o []ArgFunc) (val string, ok bool, err error) {
+	for _, n := range stack {
+		if n == name {
+			if log.IsDebug() {
+				log.Debug("Resolver=placeholder", "name", name, "stack", stack)
+			}
+			return "", false, ErrPlaceholderCycle
+		}
+	}
+
+	rs.mu.RLock()
+	p, ok := rs.providers[name]
+	rs.mu.RUnlock()
+
+	switch {
+	case ok:
+		val, err = p(rs.r, o...)
+	case strings.Contains(name, PS):
+		// the last Path() wins
+		val, err = rs.r.String(append(o[:len(o):len(o)], Path(name))...)
+	default:
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, errgo.Mask(err)
+	}
+
+	val, err = rs.expand(val, append(stack[:len(stack):len(stack)], name), o)
+	return val, true, err
+}
@@ -0,0 +1,75 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver(t *testing.T) {
+	m := config.NewManager()
+	store2 := config.ScopeStore(config.ScopeID(2))
+	assert.NoError(t, m.Write(config.Path("web/unsecure/base_url"), config.Value("http://corestore.io/")))
+	assert.NoError(t, m.Write(config.Path("web/unsecure/base_url"), config.Value("http://corestore.ch/"), store2, config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("web/unsecure/base_link_url"), config.Value("{{ web/unsecure/base_url }}index.php/")))
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("{{unsecure_base_url}}secure/{{unknown}}")))
+	assert.NoError(t, m.Write(config.Path("a/b/c"), config.Value("{{a/b/d}}")))
+	assert.NoError(t, m.Write(config.Path("a/b/d"), config.Value("{{x}}")))
+	assert.NoError(t, m.Write(config.Path("a/b/e"), config.Value("{{x/y/z}}")))
+
+	rs := config.NewResolver(m).Register("unsecure_base_url", func(r config.Reader, o ...config.ArgFunc) (string, error) {
+		return "{{web/unsecure/base_link_url}}", nil
+	})
+	rs.Register("x", func(r config.Reader, o ...config.ArgFunc) (string, error) {
+		return "{{a/b/c}}", nil
+	})
+
+	tests := []struct {
+		have    []config.ArgFunc
+		want    string
+		wantErr error
+	}{
+		{[]config.ArgFunc{config.Path("web/unsecure/base_link_url")}, "http://corestore.io/index.php/", nil},
+		{[]config.ArgFunc{config.Path("web/unsecure/base_link_url"), store2}, "http://corestore.ch/index.php/", nil},
+		{[]config.ArgFunc{config.Path("web/secure/base_url"), store2}, "http://corestore.ch/index.php/secure/{{unknown}}", nil},
+		{[]config.ArgFunc{config.Path("a/b/c")}, "", config.ErrPlaceholderCycle},
+		{[]config.ArgFunc{config.Path("a/b/e")}, "", config.ErrKeyNotFound},
+	}
+	for i, test := range tests {
+		have, err := rs.String(test.have...)
+		if test.wantErr != nil {
+			assert.EqualError(t, err, test.wantErr.Error(), "Index %d", i)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+
+	have, err := rs.Expand("{{web/unsecure/base_url}} {{", store2)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://corestore.ch/ {{", have)
+
+	c := rs.Clone().Register("x", func(r config.Reader, o ...config.ArgFunc) (string, error) {
+		return "y", nil
+	})
+	have, err = c.String(config.Path("a/b/c"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "y", have)
+	_, err = rs.String(config.Path("a/b/c"))
+	assert.EqualError(t, err, config.ErrPlaceholderCycle.Error())
+}
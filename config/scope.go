@@ -36,6 +36,9 @@ const (
 	// StringScopeWebsites defines the website scope which has default as parent and stores as child.
 	//  Stored in table core_config_data.scope.
 	ScopeRangeWebsites = "websites"
+	// ScopeRangeGroups defines the optional group scope which has default and
+	// websites as parent. Stored in table core_config_data.scope.
+	ScopeRangeGroups = "groups"
 	// StringScopeStores defines the store scope which has default and websites as parent.
 	//  Stored in table core_config_data.scope.
 	ScopeRangeStores = "stores"
@@ -57,6 +60,19 @@ type (
 	ScopeCoder interface {
 		ScopeCode() string
 	}
+	// WebsiteIDer can be implemented by a store or group ScopeIDer to provide
+	// the ID of its website. Lookups and Snapshots fall back from the store or
+	// group to the website before the default scope.
+	WebsiteIDer interface {
+		WebsiteID() int64
+	}
+	// ScopeHierarchy knows the website of a store or group, e.g. store.Manager.
+	// The Manager uses it for the fallback store -> website -> default if the
+	// ScopeIDer does not implement WebsiteIDer, see Manager.SetScopeHierarchy().
+	ScopeHierarchy interface {
+		// ParentWebsiteID returns the website ID of a store or group.
+		ParentWebsiteID(sg ScopeGroup, id int64) (int64, error)
+	}
 	// ID is convenience helper to satisfy the interface ScopeIDer.
 	ScopeID int64
 	// Code is convenience helper to satisfy the interface ScopeCoder and ScopeIDer.
@@ -92,10 +108,14 @@ func ScopeGroupNames() (r utils.StringSlice) {
 	return r.SplitStringer8(scopeGroupName, scopeGroupIndex[:]...)
 }
 
+// GetScopeGroup converts the scope of the table core_config_data into a
+// ScopeGroup. Unknown scopes return ScopeDefaultID.
 func GetScopeGroup(s string) ScopeGroup {
 	switch s {
 	case ScopeRangeWebsites:
 		return ScopeWebsiteID
+	case ScopeRangeGroups:
+		return ScopeGroupID
 	case ScopeRangeStores:
 		return ScopeStoreID
 	}
@@ -0,0 +1,269 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/juju/errgo"
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// ScopedValues groups configuration values by scope, scope ID and path. It
+	// is the document format of the file storage and of Export() and Import().
+	// Encoded maps are sorted by their keys, so the output is stable. JSON example:
+	//
+	//	{
+	//		"default": {"0": {"web/secure/base_url": "https://corestore.io/"}},
+	//		"stores":  {"2": {"web/secure/base_url": "https://corestore.ch/"}}
+	//	}
+	ScopedValues map[string]map[string]map[string]interface{}
+
+	// ImportChange describes the change of a key during an Import(). Old is
+	// nil if the key has not been set before. Overridden is true if an
+	// environment variable overrides the key, the value will not be written.
+	ImportChange struct {
+		Key        string
+		Old, New   interface{}
+		Overridden bool
+	}
+
+	// ImportChanges sorted list of all changes of an Import().
+	ImportChanges []ImportChange
+)
+
+// ReadScopedValuesJSON decodes a JSON document and validates the scopes and scope IDs.
+func ReadScopedValuesJSON(r io.Reader) (ScopedValues, error) {
+	var sv ScopedValues
+	if err := json.NewDecoder(r).Decode(&sv); err != nil {
+		return nil, errgo.Mask(err)
+	}
+	if err := sv.Validate(); err != nil {
+		return nil, err
+	}
+	return sv, nil
+}
+
+// ReadScopedValuesYAML decodes a YAML document and validates the scopes and scope IDs.
+func ReadScopedValuesYAML(r io.Reader) (ScopedValues, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	var sv ScopedValues
+	if err := yaml.Unmarshal(data, &sv); err != nil {
+		return nil, errgo.Mask(err)
+	}
+	if err := sv.Validate(); err != nil {
+		return nil, err
+	}
+	return sv, nil
+}
+
+// WriteJSON writes the indented JSON document.
+func (sv ScopedValues) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(sv, "", "  ")
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return errgo.Mask(err)
+}
+
+// WriteYAML writes the YAML document.
+func (sv ScopedValues) WriteYAML(w io.Writer) error {
+	data, err := yaml.Marshal(sv)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	_, err = w.Write(data)
+	return errgo.Mask(err)
+}
+
+// Validate checks the scopes and the scope IDs.
+func (sv ScopedValues) Validate() error {
+	for scope, ids := range sv {
+		if scope != ScopeRangeDefault && GetScopeGroup(scope) == ScopeDefaultID {
+			return errgo.Newf("Unknown scope %q", scope)
+		}
+		for id := range ids {
+			if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+				return errgo.Newf("Invalid scope ID %q in scope %q", id, scope)
+			}
+		}
+	}
+	return nil
+}
+
+// set adds a value to a fully qualified key.
+func (sv ScopedValues) set(key string, v interface{}) error {
+	scope, scopeID, path, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	id := strconv.FormatInt(scopeID, 10)
+	if sv[scope] == nil {
+		sv[scope] = make(map[string]map[string]interface{})
+	}
+	if sv[scope][id] == nil {
+		sv[scope][id] = make(map[string]interface{})
+	}
+	sv[scope][id][path] = v
+	return nil
+}
+
+// keys returns all fully qualified keys sorted.
+func (sv ScopedValues) keys() []string {
+	var keys []string
+	for scope, ids := range sv {
+		for id, paths := range ids {
+			for path := range paths {
+				keys = append(keys, scope+PS+id+PS+path)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toStorage validates the scopes and scope IDs and copies all values into a
+// new memory storage.
+func (sv ScopedValues) toStorage() (Storager, error) {
+	if err := sv.Validate(); err != nil {
+		return nil, err
+	}
+	s := NewMemoryStorage()
+	for scope, ids := range sv {
+		for id, paths := range ids {
+			for path, v := range paths {
+				if err := s.Set(scope+PS+id+PS+path, v); err != nil {
+					return nil, errgo.Mask(err)
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// String prints one change per line, usable as a diff in a dry run. Overridden
+// keys start with an exclamation mark.
+func (ic ImportChanges) String() string {
+	var buf bytes.Buffer
+	for _, c := range ic {
+		if c.Overridden {
+			fmt.Fprintf(&buf, "! %s: %v => %v (overridden)\n", c.Key, c.Old, c.New)
+			continue
+		}
+		if c.Old == nil {
+			fmt.Fprintf(&buf, "+ %s: %v\n", c.Key, c.New)
+			continue
+		}
+		fmt.Fprintf(&buf, "~ %s: %v => %v\n", c.Key, c.Old, c.New)
+	}
+	return buf.String()
+}
+
+// Export copies all values of the Storager into a ScopedValues document.
+// Default values of the SectionSlices and environment overrides will not be exported.
+func (m *Manager) Export() (ScopedValues, error) {
+	keys, err := m.storage.AllKeys()
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	sv := make(ScopedValues)
+	for _, k := range keys {
+		v, err := m.storage.Get(k)
+		if err == ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errgo.Mask(err)
+		}
+		if err := sv.set(k, v); err != nil {
+			return nil, errgo.Mask(err)
+		}
+	}
+	return sv, nil
+}
+
+// Import writes all changed values of the document via Write() without
+// bubbling. The values will be written with RawValue() because Export()
+// returns the stored values, e.g. encrypted. A value is unchanged if its
+// string representation equals the stored value of the exact scope, defaults
+// and overrides will not be considered. All values will be checked before the
+// first write: a key overridden by the environment will be reported as
+// Overridden and skipped, any other error, e.g. of the strict mode, aborts the
+// Import without writing. If dryRun is true nothing will be written. Returns
+// the changes sorted by key.
+func (m *Manager) Import(sv ScopedValues, dryRun bool) (ImportChanges, error) {
+	if err := sv.Validate(); err != nil {
+		return nil, err
+	}
+	var ic ImportChanges
+	var writes [][]ArgFunc
+	for _, k := range sv.keys() {
+		scope, scopeID, path, err := splitKey(k)
+		if err != nil {
+			return nil, err
+		}
+		nv := sv[scope][strconv.FormatInt(scopeID, 10)][path]
+
+		ov, err := m.storage.Get(k)
+		switch {
+		case err == ErrKeyNotFound:
+			ov = nil
+		case err != nil:
+			return nil, errgo.Mask(err)
+		}
+		if isEqualValue(ov, nv) {
+			continue
+		}
+
+		o := []ArgFunc{Path(path), Scope(GetScopeGroup(scope), ScopeID(scopeID)), Value(nv), NoBubble(), RawValue()}
+		c := ImportChange{Key: k, Old: ov, New: nv}
+		switch err := m.checkWrite(newArg(o...)); {
+		case err == ErrKeyOverridden:
+			c.Overridden = true
+		case err != nil:
+			return nil, err
+		default:
+			writes = append(writes, o)
+		}
+		ic = append(ic, c)
+	}
+	if dryRun {
+		return ic, nil
+	}
+	for _, o := range writes {
+		if err := m.Write(o...); err != nil {
+			return ic, errgo.Mask(err)
+		}
+	}
+	return ic, nil
+}
+
+// isEqualValue compares the database representation of two values.
+func isEqualValue(a, b interface{}) bool {
+	av, aErr := toDBValue(a)
+	bv, bErr := toDBValue(b)
+	return aErr == nil && bErr == nil && av == bv
+}
@@ -0,0 +1,180 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerExportImport(t *testing.T) {
+	staging := config.NewManager()
+	assert.NoError(t, staging.Write(config.Path("web/secure/base_url"), config.Value("https://staging.corestore.io/")))
+	assert.NoError(t, staging.Write(config.Path("web/secure/base_url"), config.Value("https://staging.corestore.ch/"), config.ScopeStore(config.ScopeID(2)), config.NoBubble()))
+	assert.NoError(t, staging.Write(config.Path("a/b/c"), config.Value(3), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+
+	sv, err := staging.Export()
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, sv.WriteJSON(&buf))
+	assert.Exactly(t, `{
+  "default": {
+    "0": {
+      "web/secure/base_url": "https://staging.corestore.io/"
+    }
+  },
+  "stores": {
+    "2": {
+      "web/secure/base_url": "https://staging.corestore.ch/"
+    }
+  },
+  "websites": {
+    "1": {
+      "a/b/c": 3
+    }
+  }
+}
+`, buf.String())
+
+	imported, err := config.ReadScopedValuesJSON(&buf)
+	assert.NoError(t, err)
+
+	production := config.NewManager()
+	assert.NoError(t, production.Write(config.Path("a/b/c"), config.Value("3"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+	assert.NoError(t, production.Write(config.Path("web/secure/base_url"), config.Value("https://corestore.io/")))
+
+	changes, err := production.Import(imported, true)
+	assert.NoError(t, err)
+	assert.Exactly(t, `~ default/0/web/secure/base_url: https://corestore.io/ => https://staging.corestore.io/
++ stores/2/web/secure/base_url: https://staging.corestore.ch/
+`, changes.String())
+	assert.Exactly(t, "https://corestore.io/", production.GetString(config.Path("web/secure/base_url")))
+
+	changes, err = production.Import(imported, false)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+	assert.Exactly(t, "https://staging.corestore.ch/", production.GetString(config.Path("web/secure/base_url"), config.ScopeStore(config.ScopeID(2))))
+
+	changes, err = production.Import(imported, true)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 0)
+
+	buf.Reset()
+	assert.NoError(t, sv.WriteYAML(&buf))
+	fromYAML, err := config.ReadScopedValuesYAML(&buf)
+	assert.NoError(t, err)
+	changes, err = production.Import(fromYAML, true)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 0)
+
+	_, err = production.Import(config.ScopedValues{"galaxy": nil}, true)
+	assert.Error(t, err)
+}
+
+func TestManagerImportDefaultsOverrides(t *testing.T) {
+	es, err := config.NewEnvStorage([]string{"CS_CONFIG__WEB__SECURE__BASE_URL=https://corestore.io/"})
+	assert.NoError(t, err)
+	m := config.NewManager(config.SetManagerEnvOverrides(es))
+	m.ApplyDefaults(config.NewConfiguration(&config.Section{
+		ID: "a",
+		Groups: config.GroupSlice{
+			&config.Group{ID: "b", Fields: config.FieldSlice{&config.Field{ID: "c", Default: "1"}}},
+		},
+	}))
+
+	sv := config.ScopedValues{"default": {"0": {
+		"a/b/c":               "1",
+		"d/e/f":               "x",
+		"web/secure/base_url": "https://corestore.io/",
+	}}}
+	changes, err := m.Import(sv, false)
+	assert.NoError(t, err)
+	// the default value and the overridden value are not stored in the scope
+	assert.Exactly(t, `+ default/0/a/b/c: 1
++ default/0/d/e/f: x
+! default/0/web/secure/base_url: <nil> => https://corestore.io/ (overridden)
+`, changes.String())
+
+	exported, err := m.Export()
+	assert.NoError(t, err)
+	assert.Exactly(t, config.ScopedValues{"default": {"0": {"a/b/c": "1", "d/e/f": "x"}}}, exported)
+}
+
+func TestManagerImportStrict(t *testing.T) {
+	m := config.NewManager(config.SetManagerStrict(config.NewConfiguration(&config.Section{
+		ID: "a",
+		Groups: config.GroupSlice{
+			&config.Group{ID: "b", Fields: config.FieldSlice{&config.Field{ID: "c"}}},
+		},
+	})))
+	_, err := m.Import(config.ScopedValues{"default": {"0": {"a/b/c": "1", "x/y/z": "2"}}}, false)
+	assert.EqualError(t, err, config.ErrFieldNotFound.Error())
+	// nothing has been written
+	assert.False(t, m.IsSet(config.Path("a/b/c")))
+}
+
+func TestSectionSliceUnmarshalJSON(t *testing.T) {
+	ss := config.NewConfiguration(
+		&config.Section{
+			ID:    "web",
+			Scope: config.ScopePermAll,
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID:    "secure",
+					Scope: config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID),
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `web/secure/base_url`,
+							ID:      "base_url",
+							Type:    config.TypeText,
+							Scope:   config.NewScopePerm(config.ScopeStoreID),
+							Visible: config.VisibleNo,
+							Default: "https://corestore.io/",
+						},
+						&config.Field{
+							// Path: `web/secure/use_in_frontend`,
+							ID:      "use_in_frontend",
+							Visible: config.VisibleYes,
+						},
+					},
+				},
+			},
+		},
+	)
+
+	var have config.SectionSlice
+	assert.NoError(t, json.Unmarshal([]byte(ss.ToJSON()), &have))
+	assert.Exactly(t, ss.ToJSON(), have.ToJSON())
+
+	f, err := have.FindFieldByPath("web/secure/base_url")
+	assert.NoError(t, err)
+	assert.Exactly(t, config.TypeText, f.Type)
+	assert.Exactly(t, config.VisibleNo, f.Visible)
+	assert.True(t, f.Scope.Has(config.ScopeStoreID))
+	assert.False(t, f.Scope.Has(config.ScopeDefaultID))
+
+	f, err = have.FindFieldByPath("web/secure/use_in_frontend")
+	assert.NoError(t, err)
+	assert.Nil(t, f.Type)
+
+	assert.Error(t, json.Unmarshal([]byte(`[{"ID":"a","Groups":[{"ID":"b","Fields":[{"ID":"c","Type":"galaxy"}]}]}]`), &have))
+	assert.Error(t, json.Unmarshal([]byte(`[{"ID":"a","Scope":["ScopeGalaxy"]}]`), &have))
+	assert.Error(t, json.Unmarshal([]byte(`[{"ID":"a","Groups":[{"ID":"b","Fields":[{"ID":"c","Visible":1}]}]}]`), &have))
+}
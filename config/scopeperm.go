@@ -14,7 +14,12 @@
 
 package config
 
-import "github.com/corestoreio/csfw/utils"
+import (
+	"encoding/json"
+
+	"github.com/corestoreio/csfw/utils"
+	"github.com/juju/errgo"
+)
 
 // ScopePerm is a bit set and used for permissions, ScopeGroup is not a part of this bit set.
 // Type ScopeGroup is a subpart of ScopePerm
@@ -63,10 +68,34 @@ func (bits ScopePerm) Human() utils.StringSlice {
 	return ret
 }
 
-// MarshalJSON implements marshaling into an array or null if no bits are set. @todo UnMarshal
+// MarshalJSON implements marshaling into an array or null if no bits are set.
 func (bits ScopePerm) MarshalJSON() ([]byte, error) {
 	if bits == 0 {
 		return []byte("null"), nil
 	}
 	return []byte(`["` + bits.Human().Join(`","`) + `"]`), nil
 }
+
+// UnmarshalJSON implements unmarshaling of an array with the names of the ScopeGroup
+// constants or null.
+func (bits *ScopePerm) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return errgo.Mask(err)
+	}
+	*bits = 0
+	for _, n := range names {
+		found := false
+		for i := ScopeDefaultID; i <= ScopeStoreID; i++ {
+			if i.String() == n {
+				bits.Set(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errgo.Newf("Unknown scope %q", n)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/csfw/utils/cast"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+type (
+	// Snapshot is an immutable view of all values of one scope. The fallback to
+	// the website and default scope has already been resolved while building,
+	// so all reads are lock-free map lookups. A Snapshot does not change after a
+	// Write, get a new one from Manager.Snapshot().
+	Snapshot struct {
+		sg     ScopeGroup
+		id     int64
+		values map[string]snapshotValue
+	}
+
+	// snapshotValue the value of a path, the scope where it has been found and
+	// if StringSlice and IntSlice split a string value by comma.
+	snapshotValue struct {
+		v     interface{}
+		sg    ScopeGroup
+		split bool
+	}
+
+	// snapshotKey identifies a cached Snapshot
+	snapshotKey struct {
+		sg          ScopeGroup
+		id, website int64
+	}
+
+	// snapshotCache maps the scopes to their Snapshots. A cache will never be
+	// modified after it has been stored in the Manager.
+	snapshotCache map[snapshotKey]*Snapshot
+)
+
+// Snapshot returns the precomputed Snapshot of a scope. For the default scope
+// id can be nil. A store or group Snapshot falls back to the website, see
+// WebsiteIDer and SetScopeHierarchy(). The Snapshot will be built on the first
+// call and cached. A Write() updates the written path in the cached Snapshots.
+func (m *Manager) Snapshot(sg ScopeGroup, id ScopeIDer) *Snapshot {
+	k := snapshotKey{sg: sg, website: -1}
+	if id == nil || sg == ScopeDefaultID || sg == ScopeAbsentID {
+		k.sg = ScopeDefaultID
+	} else {
+		k.id = id.ScopeID()
+		if wID, ok := m.websiteID(newArg(Scope(sg, id))); ok {
+			k.website = wID
+		}
+	}
+
+	if sc, ok := m.snapshots.Load().(snapshotCache); ok {
+		if s, ok := sc[k]; ok {
+			return s
+		}
+	}
+
+	// building reads all keys so it runs without holding snapMu. A Write in
+	// the meantime changes the generation and the Snapshot won't be cached.
+	gen := atomic.LoadUint64(&m.snapGen)
+	s := m.buildSnapshot(k)
+
+	m.snapMu.Lock()
+	defer m.snapMu.Unlock()
+	sc, _ := m.snapshots.Load().(snapshotCache)
+	if cs, ok := sc[k]; ok {
+		return cs
+	}
+	if gen != atomic.LoadUint64(&m.snapGen) {
+		return s
+	}
+	nsc := make(snapshotCache, len(sc)+1)
+	for key, val := range sc {
+		nsc[key] = val
+	}
+	nsc[k] = s
+	m.snapshots.Store(nsc)
+	return s
+}
+
+// InvalidateSnapshots removes all cached Snapshots. Call it after changing the
+// underlying Storager without the Manager or after changing the hierarchy of
+// the scopes.
+func (m *Manager) InvalidateSnapshots() {
+	m.snapMu.Lock()
+	atomic.AddUint64(&m.snapGen, 1)
+	m.snapshots.Store(snapshotCache{})
+	m.snapMu.Unlock()
+}
+
+// updateSnapshots resolves the path again in all cached Snapshots. Snapshots
+// whose value changes will be copied, so already returned Snapshots stay
+// immutable.
+func (m *Manager) updateSnapshots(path string) {
+	m.snapMu.Lock()
+	defer m.snapMu.Unlock()
+	atomic.AddUint64(&m.snapGen, 1)
+	sc, _ := m.snapshots.Load().(snapshotCache)
+	if len(sc) == 0 {
+		return
+	}
+	nsc := make(snapshotCache, len(sc))
+	for k, s := range sc {
+		nsc[k] = s
+		sv, found, err := m.resolveSnapshotValue(k, path)
+		if err != nil {
+			// let the next Snapshot() call build it again
+			delete(nsc, k)
+			continue
+		}
+		if ov, ok := s.values[path]; ok == found && (!found || (ov.sg == sv.sg && ov.split == sv.split && reflect.DeepEqual(ov.v, sv.v))) {
+			continue
+		}
+		ns := &Snapshot{
+			sg:     s.sg,
+			id:     s.id,
+			values: make(map[string]snapshotValue, len(s.values)+1),
+		}
+		for p, v := range s.values {
+			ns.values[p] = v
+		}
+		if found {
+			ns.values[path] = sv
+		} else {
+			delete(ns.values, path)
+		}
+		nsc[k] = ns
+	}
+	m.snapshots.Store(nsc)
+}
+
+// snapshotChain returns the scopes from the scope of the key down to the
+// default scope.
+func snapshotChain(k snapshotKey) []*arg {
+	chain := []*arg{newArg(Scope(k.sg, ScopeID(k.id)))}
+	if k.website >= 0 {
+		chain = append(chain, newArg(ScopeWebsite(ScopeID(k.website))))
+	}
+	if k.sg != ScopeDefaultID {
+		chain = append(chain, newArg(Scope(ScopeDefaultID, nil)))
+	}
+	return chain
+}
+
+// resolveSnapshotValue returns the value of the path in the first scope of the
+// chain of the key. The value passes the AfterLoad() hook of the BackendModel.
+// found is false if no scope contains the path. The comma list flag of the
+// Field gets recorded so that reading a Snapshot never locks the Manager.
+func (m *Manager) resolveSnapshotValue(k snapshotKey, path string) (sv snapshotValue, found bool, err error) {
+	for _, a := range snapshotChain(k) {
+		a.p = path
+		v, err := m.getKey(a.scopePath())
+		if err == ErrKeyNotFound {
+			continue
+		}
+		if err == nil {
+			v, err = afterLoad(m.fields(), m, a, v)
+		}
+		if err != nil {
+			log.Error("Manager=resolveSnapshotValue", "err", err, "key", a.scopePath())
+			return sv, false, err
+		}
+		return snapshotValue{v: v, sg: a.s, split: m.isCommaList(path)}, true, nil
+	}
+	return sv, false, nil
+}
+
+// buildSnapshot resolves all known paths for the scope of the key.
+func (m *Manager) buildSnapshot(k snapshotKey) *Snapshot {
+	s := &Snapshot{
+		sg:     k.sg,
+		id:     k.id,
+		values: make(map[string]snapshotValue),
+	}
+	for _, key := range m.AllKeys() {
+		_, _, p, err := splitKey(key)
+		if err != nil {
+			continue
+		}
+		if _, ok := s.values[p]; ok {
+			continue
+		}
+		if sv, found, err := m.resolveSnapshotValue(k, p); err == nil && found {
+			s.values[p] = sv
+		}
+	}
+	if log.IsDebug() {
+		log.Debug("Manager=buildSnapshot", "scope", k.sg, "id", k.id, "website", k.website, "paths", len(s.values))
+	}
+	return s
+}
+
+// ScopeGroup returns the scope of the Snapshot.
+func (s *Snapshot) ScopeGroup() ScopeGroup { return s.sg }
+
+// ScopeID returns the ID of the scope. Implements ScopeIDer.
+func (s *Snapshot) ScopeID() int64 { return s.id }
+
+// Len returns the number of paths in the Snapshot.
+func (s *Snapshot) Len() int { return len(s.values) }
+
+// Lookup returns the raw value of a path, e.g. web/secure/base_url, and the
+// scope where the value has been found. Returns ErrKeyNotFound if the path has
+// not been set in any scope of the chain.
+func (s *Snapshot) Lookup(path string) (interface{}, ScopeGroup, error) {
+	sv, ok := s.values[path]
+	if !ok {
+		return nil, ScopeAbsentID, ErrKeyNotFound
+	}
+	return sv.v, sv.sg, nil
+}
+
+// String returns a string. Error behaviour see Manager.String.
+func (s *Snapshot) String(path string) (string, error) {
+	vs, _, err := s.Lookup(path)
+	if err != nil {
+		return "", err
+	}
+	str, err := cast.ToStringE(vs)
+	return str, errgo.Mask(err)
+}
+
+// GetString returns a string or an empty string on error.
+func (s *Snapshot) GetString(path string) string {
+	str, _ := s.String(path)
+	return str
+}
+
+// StringSlice returns a slice of strings. Decoding see Manager.StringSlice.
+func (s *Snapshot) StringSlice(path string) ([]string, error) {
+	sv, ok := s.values[path]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return ToStringSlice(sv.v, sv.split)
+}
+
+// IntSlice returns a slice of ints. Decoding see Manager.IntSlice.
+func (s *Snapshot) IntSlice(path string) ([]int, error) {
+	sv, ok := s.values[path]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return ToIntSlice(sv.v, sv.split)
+}
+
+// Bool returns a bool. Error behaviour see Manager.String.
+func (s *Snapshot) Bool(path string) (bool, error) {
+	vs, _, err := s.Lookup(path)
+	if err != nil {
+		return false, err
+	}
+	b, err := cast.ToBoolE(vs)
+	return b, errgo.Mask(err)
+}
+
+// GetBool returns a bool or false on error.
+func (s *Snapshot) GetBool(path string) bool {
+	b, _ := s.Bool(path)
+	return b
+}
+
+// Float64 returns a float64. Error behaviour see Manager.String.
+func (s *Snapshot) Float64(path string) (float64, error) {
+	vs, _, err := s.Lookup(path)
+	if err != nil {
+		return 0.0, err
+	}
+	f, err := cast.ToFloat64E(vs)
+	return f, errgo.Mask(err)
+}
+
+// Int returns an int. Error behaviour see Manager.String.
+func (s *Snapshot) Int(path string) (int, error) {
+	vs, _, err := s.Lookup(path)
+	if err != nil {
+		return 0, err
+	}
+	i, err := cast.ToIntE(vs)
+	return i, errgo.Mask(err)
+}
+
+// GetInt returns an int or 0 on error.
+func (s *Snapshot) GetInt(path string) int {
+	i, _ := s.Int(path)
+	return i
+}
+
+// DateTime returns a date and time object. Error behaviour see Manager.String.
+func (s *Snapshot) DateTime(path string) (time.Time, error) {
+	vs, _, err := s.Lookup(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := cast.ToTimeE(vs)
+	return t, errgo.Mask(err)
+}
@@ -0,0 +1,186 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// storeWebsite is a store ScopeIDer which knows its website
+type storeWebsite struct {
+	storeID, websiteID int64
+}
+
+func (sw storeWebsite) ScopeID() int64   { return sw.storeID }
+func (sw storeWebsite) WebsiteID() int64 { return sw.websiteID }
+
+func TestManagerSnapshot(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(htmlTestSections)
+	assert.NoError(t, m.Write(config.Path("web/cookie/cookie_path"), config.Value("/website"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path("web/cookie/start"), config.Value("8,0,0"), config.ScopeStore(config.ScopeID(2)), config.NoBubble()))
+
+	s := m.Snapshot(config.ScopeStoreID, storeWebsite{2, 1})
+	assert.Exactly(t, config.ScopeStoreID, s.ScopeGroup())
+	assert.Exactly(t, int64(2), s.ScopeID())
+	assert.True(t, s == m.Snapshot(config.ScopeStoreID, storeWebsite{2, 1}), "Snapshot must be cached")
+
+	tests := []struct {
+		path    string
+		wantVal interface{}
+		wantSG  config.ScopeGroup
+	}{
+		{"web/cookie/start", "8,0,0", config.ScopeStoreID},
+		{"web/cookie/cookie_path", "/website", config.ScopeWebsiteID},
+		{"web/cookie/hidden", "h", config.ScopeDefaultID},
+	}
+	for _, test := range tests {
+		v, sg, err := s.Lookup(test.path)
+		assert.NoError(t, err, test.path)
+		assert.Exactly(t, test.wantVal, v, test.path)
+		assert.Exactly(t, test.wantSG, sg, test.path)
+	}
+	_, _, err := s.Lookup("web/cookie/not_found")
+	assert.EqualError(t, err, config.ErrKeyNotFound.Error())
+
+	b, err := s.Bool("web/cookie/cookie_httponly")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	// without a website the store falls back to the default scope
+	assert.Exactly(t, "/", m.Snapshot(config.ScopeStoreID, config.ScopeID(2)).GetString("web/cookie/cookie_path"))
+	assert.Exactly(t, "/website", m.Snapshot(config.ScopeWebsiteID, config.ScopeID(1)).GetString("web/cookie/cookie_path"))
+	assert.Exactly(t, "/", m.Snapshot(config.ScopeDefaultID, nil).GetString("web/cookie/cookie_path"))
+
+	// a Write creates a new Snapshot and the old one stays unchanged
+	assert.NoError(t, m.Write(config.Path("web/cookie/cookie_path"), config.Value("/store"), config.ScopeStore(config.ScopeID(2)), config.NoBubble()))
+	s2 := m.Snapshot(config.ScopeStoreID, storeWebsite{2, 1})
+	assert.False(t, s == s2)
+	assert.Exactly(t, "/website", s.GetString("web/cookie/cookie_path"))
+	assert.Exactly(t, "/store", s2.GetString("web/cookie/cookie_path"))
+}
+
+func TestManagerSnapshotWriteUpdates(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(htmlTestSections)
+
+	s1 := m.Snapshot(config.ScopeStoreID, storeWebsite{1, 1})
+	s2 := m.Snapshot(config.ScopeStoreID, storeWebsite{2, 2})
+
+	// only the Snapshot of store 1 contains a changed value
+	assert.NoError(t, m.Write(config.Path("web/cookie/cookie_path"), config.Value("/w1"), config.ScopeWebsite(config.ScopeID(1)), config.NoBubble()))
+	assert.True(t, s2 == m.Snapshot(config.ScopeStoreID, storeWebsite{2, 2}), "Snapshot of store 2 must be kept")
+	ns1 := m.Snapshot(config.ScopeStoreID, storeWebsite{1, 1})
+	assert.False(t, s1 == ns1)
+	assert.Exactly(t, "/", s1.GetString("web/cookie/cookie_path"))
+	assert.Exactly(t, "/w1", ns1.GetString("web/cookie/cookie_path"))
+	assert.Exactly(t, s1.Len(), ns1.Len())
+
+	// a new path will be added to all Snapshots
+	assert.NoError(t, m.Write(config.Path("web/cookie/new_path"), config.Value("n")))
+	assert.Exactly(t, "n", m.Snapshot(config.ScopeStoreID, storeWebsite{2, 2}).GetString("web/cookie/new_path"))
+	assert.Exactly(t, s2.Len()+1, m.Snapshot(config.ScopeStoreID, storeWebsite{2, 2}).Len())
+
+	is, err := m.Snapshot(config.ScopeDefaultID, nil).IntSlice("web/cookie/new_path")
+	assert.Nil(t, is)
+	assert.Error(t, err)
+}
+
+func TestManagerSnapshotCommaList(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(config.NewConfiguration(
+		&config.Section{
+			ID: "catalog",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "frontend",
+					Fields: config.FieldSlice{
+						&config.Field{ID: "list_mode", Type: config.TypeMultiselect, Default: "grid,list"},
+						&config.Field{ID: "title", Type: config.TypeSelect, Default: "a,b"},
+					},
+				},
+			},
+		},
+	))
+	assert.NoError(t, m.Write(config.Path("design/seo/ids"), config.Value("3,4"), config.ScopeStore(config.ScopeID(1)), config.NoBubble()))
+
+	s := m.Snapshot(config.ScopeStoreID, storeWebsite{1, 1})
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"catalog/frontend/list_mode", []string{"grid", "list"}},
+		{"catalog/frontend/title", []string{"a,b"}},
+		{"design/seo/ids", []string{"3", "4"}}, // unknown Field
+	}
+	for i, test := range tests {
+		have, err := s.StringSlice(test.path)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+
+	// the flag has been recorded while building and does not change with the
+	// sections of the Manager, a new Snapshot gets the new flag.
+	m.ApplyDefaults(config.NewConfiguration(
+		&config.Section{
+			ID: "design",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "seo",
+					Fields: config.FieldSlice{
+						&config.Field{ID: "ids", Type: config.TypeText, Default: "1"},
+					},
+				},
+			},
+		},
+	))
+	is, err := s.IntSlice("design/seo/ids")
+	assert.NoError(t, err)
+	assert.Exactly(t, []int{3, 4}, is)
+	ss, err := m.Snapshot(config.ScopeStoreID, storeWebsite{1, 1}).StringSlice("design/seo/ids")
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"3,4"}, ss)
+}
+
+func TestManagerSnapshotConcurrent(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(htmlTestSections)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				s := m.Snapshot(config.ScopeStoreID, storeWebsite{int64(i % 3), 1})
+				if s.GetString("web/cookie/hidden") != "h" {
+					t.Error("Expecting value h")
+				}
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			if err := m.Write(config.Path("web/cookie/cookie_path"), config.Value("/w"), config.ScopeWebsite(config.ScopeID(1))); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Exactly(t, "/w", m.Snapshot(config.ScopeStoreID, storeWebsite{2, 1}).GetString("web/cookie/cookie_path"))
+}
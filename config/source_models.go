@@ -0,0 +1,56 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+type (
+	// SourceYesNo provides the options Yes (1) and No (0).
+	// Magento\Config\Model\Config\Source\Yesno
+	SourceYesNo struct{}
+
+	// SourceEnableDisable provides the options Enable (1) and Disable (0).
+	// Magento\Config\Model\Config\Source\Enabledisable
+	SourceEnableDisable struct{}
+)
+
+var (
+	_ FieldSourceModeller = (*SourceYesNo)(nil)
+	_ FieldSourceModeller = (*SourceEnableDisable)(nil)
+)
+
+// NewSourceYesNo creates a new yes/no source model.
+func NewSourceYesNo() *SourceYesNo {
+	return &SourceYesNo{}
+}
+
+// Options returns Yes and No
+func (sy *SourceYesNo) Options() ValueLabelSlice {
+	return ValueLabelSlice{
+		ValueLabel{Value: "1", Label: "Yes"},
+		ValueLabel{Value: "0", Label: "No"},
+	}
+}
+
+// NewSourceEnableDisable creates a new enable/disable source model.
+func NewSourceEnableDisable() *SourceEnableDisable {
+	return &SourceEnableDisable{}
+}
+
+// Options returns Enable and Disable
+func (se *SourceEnableDisable) Options() ValueLabelSlice {
+	return ValueLabelSlice{
+		ValueLabel{Value: "1", Label: "Enable"},
+		ValueLabel{Value: "0", Label: "Disable"},
+	}
+}
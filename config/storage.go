@@ -0,0 +1,187 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrStorageReadOnly gets returned when writing into a read only Storager.
+var ErrStorageReadOnly = errors.New("Storage is read only")
+
+// ErrKeyOverridden gets returned when writing a key which is already set in a
+// storage with a higher precedence, e.g. a read only file layer. The written
+// value would never be returned.
+var ErrKeyOverridden = errors.New("Key is overridden by a storage with higher precedence")
+
+// ErrInvalidKey gets returned when a key cannot be split into scope, scope ID and path.
+var ErrInvalidKey = errors.New("Invalid key, expecting scope/scopeID/path")
+
+type (
+	// Storager is the underlying data storage for holding the keys and its values.
+	// A key is the fully qualified path e.g. stores/2/web/secure/base_url.
+	// Setting a nil value removes the key.
+	Storager interface {
+		// Set writes a key with its value into the storage.
+		Set(key string, value interface{}) error
+		// Get returns ErrKeyNotFound if a key cannot be found.
+		Get(key string) (interface{}, error)
+		// AllKeys returns all available keys.
+		AllKeys() ([]string, error)
+	}
+
+	// memoryStorage default thread safe in-memory storage.
+	memoryStorage struct {
+		mu   sync.RWMutex
+		data map[string]interface{}
+	}
+
+	// readOnlyStorage wraps a Storager and rejects all writes.
+	readOnlyStorage struct {
+		Storager
+	}
+
+	// layeredStorage asks each layer in the order of the slice.
+	layeredStorage []Storager
+)
+
+var (
+	_ Storager = (*memoryStorage)(nil)
+	_ Storager = (*readOnlyStorage)(nil)
+	_ Storager = (*layeredStorage)(nil)
+)
+
+// NewMemoryStorage creates a new thread safe in-memory Storager.
+func NewMemoryStorage() Storager {
+	return &memoryStorage{
+		data: make(map[string]interface{}),
+	}
+}
+
+func (ms *memoryStorage) Set(key string, value interface{}) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if value == nil {
+		delete(ms.data, key)
+		return nil
+	}
+	ms.data[key] = value
+	return nil
+}
+
+func (ms *memoryStorage) Get(key string) (interface{}, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	if v, ok := ms.data[key]; ok {
+		return v, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (ms *memoryStorage) AllKeys() ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	keys := make([]string, 0, len(ms.data))
+	for k := range ms.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// NewReadOnlyStorage wraps a Storager and returns ErrStorageReadOnly on all writes.
+func NewReadOnlyStorage(s Storager) Storager {
+	return readOnlyStorage{Storager: s}
+}
+
+func (ros readOnlyStorage) Set(_ string, _ interface{}) error {
+	return ErrStorageReadOnly
+}
+
+// NewLayeredStorage creates a Storager from several layers. The order of the
+// arguments defines the precedence: the first layer has the highest. A deploy
+// may use NewLayeredStorage(env, file, db). Get returns the value from the first
+// layer which contains the key. Set writes into the first layer which is not
+// read only and returns ErrKeyOverridden if a read only layer before already
+// contains the key.
+func NewLayeredStorage(layers ...Storager) Storager {
+	ls := make(layeredStorage, 0, len(layers))
+	for _, l := range layers {
+		if l != nil {
+			ls = append(ls, l)
+		}
+	}
+	return ls
+}
+
+func (ls layeredStorage) Set(key string, value interface{}) error {
+	for _, l := range ls {
+		err := l.Set(key, value)
+		if err != ErrStorageReadOnly {
+			return err
+		}
+		if _, err := l.Get(key); err == nil {
+			return ErrKeyOverridden
+		}
+	}
+	return ErrStorageReadOnly
+}
+
+func (ls layeredStorage) Get(key string) (interface{}, error) {
+	for _, l := range ls {
+		v, err := l.Get(key)
+		if err == ErrKeyNotFound {
+			continue
+		}
+		return v, err
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (ls layeredStorage) AllKeys() ([]string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, l := range ls {
+		lk, err := l.AllKeys()
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range lk {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// splitKey splits a fully qualified key e.g. stores/2/web/secure/base_url into
+// its scope, scope ID and path.
+func splitKey(key string) (scope string, scopeID int64, path string, err error) {
+	parts := strings.SplitN(key, PS, 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return "", 0, "", ErrInvalidKey
+	}
+	if scopeID, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return "", 0, "", ErrInvalidKey
+	}
+	return parts[0], scopeID, parts[2], nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sync"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// DBStorage uses the table core_config_data as Storager. All rows will be
+// cached in memory after calling Load(). Set() upserts the value in the table,
+// or deletes the row of a nil value, and updates the cache. A NULL value in
+// the table is treated as not set.
+type DBStorage struct {
+	dbrSess dbr.SessionRunner
+	mu      sync.RWMutex
+	cache   Storager
+}
+
+var _ Storager = (*DBStorage)(nil)
+
+// NewDBStorage creates a new Storager for the table core_config_data. Call
+// Load() to fill the cache.
+func NewDBStorage(dbrSess dbr.SessionRunner) *DBStorage {
+	return &DBStorage{
+		dbrSess: dbrSess,
+		cache:   NewMemoryStorage(),
+	}
+}
+
+// Load reads all rows of core_config_data into the cache. Can be called
+// several times to refresh the cache.
+func (dbs *DBStorage) Load() error {
+	ccd, err := loadCoreConfigData(dbs.dbrSess)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	cache := NewMemoryStorage()
+	for _, cd := range ccd {
+		if cd == nil || false == cd.Value.Valid {
+			continue
+		}
+		key := newArg(Path(cd.Path), Scope(GetScopeGroup(cd.Scope), ScopeID(cd.ScopeID))).scopePath()
+		if err := cache.Set(key, cd.Value.String); err != nil {
+			return errgo.Mask(err)
+		}
+	}
+	if log.IsDebug() {
+		log.Debug("DBStorage=Load", "rows", len(ccd))
+	}
+	dbs.mu.Lock()
+	dbs.cache = cache
+	dbs.mu.Unlock()
+	return nil
+}
+
+// Set upserts the value into core_config_data. A nil value deletes the row and
+// removes the key like DBWriter.Write does.
+func (dbs *DBStorage) Set(key string, value interface{}) error {
+	scope, scopeID, path, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	val, err := toDBValue(value)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	if err := writeCoreConfigData(dbs.dbrSess, scope, scopeID, path, val); err != nil {
+		return errgo.Mask(err)
+	}
+	dbs.mu.RLock()
+	defer dbs.mu.RUnlock()
+	return dbs.cache.Set(key, value)
+}
+
+// Get returns a value from the cache.
+func (dbs *DBStorage) Get(key string) (interface{}, error) {
+	dbs.mu.RLock()
+	defer dbs.mu.RUnlock()
+	return dbs.cache.Get(key)
+}
+
+// AllKeys returns all keys from the cache.
+func (dbs *DBStorage) AllKeys() ([]string, error) {
+	dbs.mu.RLock()
+	defer dbs.mu.RUnlock()
+	return dbs.cache.AllKeys()
+}
@@ -0,0 +1,191 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// EnvPrefix is the prefix of all environment variables which override a
+// configuration path.
+const EnvPrefix = "CS_CONFIG"
+
+// envPathSep separates the scope and the path parts in the name of an
+// environment variable. Path parts can contain single underscores.
+const envPathSep = "__"
+
+type (
+	// ScopeCodeResolver returns the ID of a website or store code. Used in the
+	// EnvStorage to resolve e.g. CS_CONFIG_STORES_DE__... where DE is the store code.
+	ScopeCodeResolver func(sg ScopeGroup, code string) (int64, error)
+
+	// EnvStorage read only Storager which maps environment variables onto
+	// scopes and paths. The name of the variable follows the pattern:
+	//	CS_CONFIG[_<WEBSITES|STORES>_<ID|CODE>]__<SECTION>__<GROUP>__<FIELD>
+	// Examples:
+	//	CS_CONFIG__WEB__SECURE__BASE_URL            => default/0/web/secure/base_url
+	//	CS_CONFIG_WEBSITES_1__WEB__SECURE__BASE_URL => websites/1/web/secure/base_url
+	//	CS_CONFIG_STORES_DE__WEB__SECURE__BASE_URL  => stores/<ID of DE>/web/secure/base_url
+	// Names and codes are case insensitive and will be lower cased.
+	EnvStorage struct {
+		Storager
+		resolver ScopeCodeResolver
+		// strict returns an error for variables which cannot be mapped
+		strict bool
+		// names contains the key and as value the name of the environment variable
+		names map[string]string
+	}
+
+	// EnvStorageOption option func for NewEnvStorage()
+	EnvStorageOption func(*EnvStorage)
+)
+
+var _ Storager = (*EnvStorage)(nil)
+
+// SetEnvScopeCodeResolver sets the function to resolve website and store codes.
+// Without a resolver only numeric scope IDs can be used.
+func SetEnvScopeCodeResolver(r ScopeCodeResolver) EnvStorageOption {
+	return func(es *EnvStorage) { es.resolver = r }
+}
+
+// SetEnvStrict returns an error in NewEnvStorage() for a variable with the
+// EnvPrefix which cannot be mapped onto a scope and path. Default: such
+// variables will be logged and skipped.
+func SetEnvStrict(strict bool) EnvStorageOption {
+	return func(es *EnvStorage) { es.strict = strict }
+}
+
+// NewEnvStorage creates a read only Storager from environ, mostly os.Environ().
+// Variables without the EnvPrefix will be ignored. A variable with the prefix
+// which cannot be mapped onto a scope and path will be logged and skipped, or
+// returns an error in strict mode, see SetEnvStrict().
+func NewEnvStorage(environ []string, opts ...EnvStorageOption) (*EnvStorage, error) {
+	es := &EnvStorage{
+		names: make(map[string]string),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(es)
+		}
+	}
+
+	ms := NewMemoryStorage()
+	for _, kv := range environ {
+		if false == strings.HasPrefix(kv, EnvPrefix+"_") {
+			continue
+		}
+		eq := strings.IndexByte(kv, '=')
+		if eq < 0 {
+			continue
+		}
+		name, value := kv[:eq], kv[eq+1:]
+		key, err := es.envToKey(name)
+		if err != nil {
+			if es.strict {
+				return nil, errgo.Mask(err)
+			}
+			log.Warn("config.NewEnvStorage.envToKey", "err", err, "name", name)
+			continue
+		}
+		if err := ms.Set(key, value); err != nil {
+			return nil, errgo.Mask(err)
+		}
+		es.names[key] = name
+	}
+	es.Storager = NewReadOnlyStorage(ms)
+	return es, nil
+}
+
+// envToKey converts the name of an environment variable into a fully qualified key.
+func (es *EnvStorage) envToKey(name string) (string, error) {
+	parts := strings.Split(strings.ToLower(name[len(EnvPrefix):]), envPathSep)
+	if len(parts) != 4 {
+		return "", errgo.Newf("Cannot map %s onto a path. Expecting %s[_<SCOPE>_<ID|CODE>]__<SECTION>__<GROUP>__<FIELD>", name, EnvPrefix)
+	}
+	path := parts[1] + PS + parts[2] + PS + parts[3]
+
+	if parts[0] == "" {
+		return ScopeRangeDefault + PS + "0" + PS + path, nil
+	}
+
+	scope := strings.SplitN(strings.TrimPrefix(parts[0], "_"), "_", 2)
+	if len(scope) != 2 || scope[1] == "" {
+		return "", errgo.Newf("Missing scope ID or code in %s", name)
+	}
+	sg := GetScopeGroup(scope[0])
+	if sg == ScopeDefaultID {
+		return "", errgo.Newf("Unknown scope %q in %s", scope[0], name)
+	}
+
+	id, err := strconv.ParseInt(scope[1], 10, 64)
+	if err != nil {
+		if es.resolver == nil {
+			return "", errgo.Newf("Cannot resolve scope code %q in %s without a ScopeCodeResolver", scope[1], name)
+		}
+		if id, err = es.resolver(sg, scope[1]); err != nil {
+			return "", errgo.Mask(err)
+		}
+	}
+	return scope[0] + PS + strconv.FormatInt(id, 10) + PS + path, nil
+}
+
+// Name returns the name of the environment variable for a key.
+func (es *EnvStorage) Name(key string) string {
+	return es.names[key]
+}
+
+// SetManagerEnvOverrides adds the EnvStorage as overrides to the Manager. The
+// overrides have the highest precedence and cannot be changed by Write(), which
+// returns ErrKeyOverridden, or by ApplyCoreConfigData().
+func SetManagerEnvOverrides(es *EnvStorage) ManagerOption {
+	return func(m *Manager) {
+		m.overrides = es
+	}
+}
+
+// DebugOverrides lists each overridden key with the name of the environment
+// variable, the new value and the value which would be used without the override.
+func (m *Manager) DebugOverrides() string {
+	if m.overrides == nil {
+		return ""
+	}
+	keys, err := m.overrides.AllKeys()
+	if err != nil {
+		return err.Error()
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, k := range keys {
+		ov, _ := m.overrides.Get(k)
+		fmt.Fprintf(&buf, "%s => %s: %q", m.overrides.Name(k), k, ov)
+		v, err := m.storage.Get(k)
+		if err == ErrKeyNotFound {
+			v, err = m.defaults.Get(k)
+		}
+		if err == nil {
+			fmt.Fprintf(&buf, " overrides %v", v)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
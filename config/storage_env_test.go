@@ -0,0 +1,100 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func envResolver(sg config.ScopeGroup, code string) (int64, error) {
+	if sg == config.ScopeStoreID && code == "de" {
+		return 2, nil
+	}
+	return 0, errors.New("Code not found")
+}
+
+func TestEnvStorage(t *testing.T) {
+	es, err := config.NewEnvStorage([]string{
+		"PATH=/usr/bin",
+		"CS_CONFIGURATION=ignored",
+		"CS_CONFIG__WEB__SECURE__BASE_URL=https://corestore.io/",
+		"CS_CONFIG_WEBSITES_1__WEB__SECURE__BASE_URL=https://corestore.ch/",
+		"CS_CONFIG_STORES_DE__WEB__SECURE__BASE_URL=https://corestore.de/",
+	}, config.SetEnvScopeCodeResolver(envResolver))
+	assert.NoError(t, err)
+
+	keys, err := es.AllKeys()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"default/0/web/secure/base_url", "stores/2/web/secure/base_url", "websites/1/web/secure/base_url"}, keys)
+	assert.Exactly(t, "CS_CONFIG_STORES_DE__WEB__SECURE__BASE_URL", es.Name("stores/2/web/secure/base_url"))
+	assert.Exactly(t, config.ErrStorageReadOnly, es.Set("default/0/a/b/c", 1))
+
+	mem := config.NewMemoryStorage()
+	assert.NoError(t, mem.Set("default/0/web/secure/base_url", "http://localhost/"))
+	m := config.NewManager(config.SetManagerStorage(mem), config.SetManagerEnvOverrides(es))
+	assert.EqualError(t, m.Write(config.Path("web/secure/base_url"), config.Value("http://127.0.0.1/")), config.ErrKeyOverridden.Error())
+	// the bubbled value would be hidden in the default scope
+	assert.EqualError(t, m.Write(config.Path("web/secure/base_url"), config.Value("http://localhost:8080/"), config.ScopeStore(config.ScopeID(3))), config.ErrKeyOverridden.Error())
+	assert.NoError(t, m.Write(config.Path("web/secure/base_url"), config.Value("http://localhost:8080/"), config.ScopeStore(config.ScopeID(3)), config.NoBubble()))
+
+	assert.Exactly(t, "https://corestore.io/", m.GetString(config.Path("web/secure/base_url")))
+	assert.Exactly(t, "https://corestore.de/", m.GetString(config.Path("web/secure/base_url"), config.ScopeStore(config.ScopeID(2))))
+	assert.Exactly(t, "http://localhost:8080/", m.GetString(config.Path("web/secure/base_url"), config.ScopeStore(config.ScopeID(3))))
+	assert.Exactly(t, "https://corestore.ch/", m.GetString(config.Path("web/secure/base_url"), config.ScopeWebsite(config.ScopeID(1))))
+
+	assert.Exactly(t, `CS_CONFIG__WEB__SECURE__BASE_URL => default/0/web/secure/base_url: "https://corestore.io/" overrides http://localhost/
+CS_CONFIG_STORES_DE__WEB__SECURE__BASE_URL => stores/2/web/secure/base_url: "https://corestore.de/"
+CS_CONFIG_WEBSITES_1__WEB__SECURE__BASE_URL => websites/1/web/secure/base_url: "https://corestore.ch/"
+`, m.DebugOverrides())
+}
+
+func TestEnvStorageErrors(t *testing.T) {
+	tests := []string{
+		"CS_CONFIG__WEB__SECURE=x",
+		"CS_CONFIG_GALAXY_1__WEB__SECURE__BASE_URL=x",
+		"CS_CONFIG_STORES__WEB__SECURE__BASE_URL=x",
+		"CS_CONFIG_STORES_DE__WEB__SECURE__BASE_URL=x",
+	}
+	for i, env := range tests {
+		_, err := config.NewEnvStorage([]string{env}, config.SetEnvStrict(true))
+		assert.Error(t, err, "Index %d", i)
+	}
+	_, err := config.NewEnvStorage([]string{"CS_CONFIG_WEBSITES_XX__WEB__SECURE__BASE_URL=x"}, config.SetEnvScopeCodeResolver(envResolver), config.SetEnvStrict(true))
+	assert.EqualError(t, err, "Code not found")
+
+	// without strict mode unmappable variables will be skipped
+	es, err := config.NewEnvStorage(append(tests, "CS_CONFIG__WEB__SECURE__BASE_URL=https://corestore.io/"))
+	assert.NoError(t, err)
+	keys, err := es.AllKeys()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"default/0/web/secure/base_url"}, keys)
+}
+
+func TestEnvStorageWriteNoPublish(t *testing.T) {
+	es, err := config.NewEnvStorage([]string{"CS_CONFIG__WEB__SECURE__BASE_URL=https://corestore.io/"})
+	assert.NoError(t, err)
+	m := config.NewManager(config.SetManagerEnvOverrides(es))
+	sub := &testSubscriber{}
+	_, err = m.Subscribe("web", config.ScopeAbsentID, nil, sub)
+	assert.NoError(t, err)
+
+	assert.EqualError(t, m.Write(config.Path("web/secure/base_url"), config.Value("http://localhost/")), config.ErrKeyOverridden.Error())
+	assert.Empty(t, sub.paths)
+	assert.Exactly(t, "https://corestore.io/", m.GetString(config.Path("web/secure/base_url")))
+}
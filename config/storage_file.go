@@ -0,0 +1,73 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errgo"
+)
+
+// ErrFileFormat gets returned when the file extension is neither json, yaml nor yml.
+var ErrFileFormat = errors.New("Unknown file format, expecting .json, .yaml or .yml")
+
+// NewJSONStorage creates a new read only Storager from a JSON document.
+// The format is described in ScopedValues.
+func NewJSONStorage(r io.Reader) (Storager, error) {
+	sv, err := ReadScopedValuesJSON(r)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	s, err := sv.toStorage()
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	return NewReadOnlyStorage(s), nil
+}
+
+// NewYAMLStorage creates a new read only Storager from a YAML document.
+// The format is described in ScopedValues.
+func NewYAMLStorage(r io.Reader) (Storager, error) {
+	sv, err := ReadScopedValuesYAML(r)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	s, err := sv.toStorage()
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	return NewReadOnlyStorage(s), nil
+}
+
+// NewFileStorage creates a new read only Storager from a JSON or YAML file.
+// The file extension defines the format.
+func NewFileStorage(filename string) (Storager, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	defer f.Close()
+
+	switch filepath.Ext(filename) {
+	case ".json":
+		return NewJSONStorage(f)
+	case ".yaml", ".yml":
+		return NewYAMLStorage(f)
+	}
+	return nil, ErrFileFormat
+}
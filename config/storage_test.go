@@ -0,0 +1,184 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/stretchr/testify/assert"
+)
+
+const storageJSON = `{
+	"default": {"0": {"web/secure/base_url": "https://corestore.io/", "a/b/c": 1}},
+	"stores":  {"2": {"web/secure/base_url": "https://corestore.ch/"}}
+}`
+
+const storageYAML = `
+default:
+  0:
+    web/secure/base_url: https://corestore.io/
+    currency/options/allow:
+      - USD
+      - EUR
+websites:
+  1:
+    web/secure/base_url: https://corestore.de/
+`
+
+func TestMemoryStorage(t *testing.T) {
+	s := config.NewMemoryStorage()
+	assert.NoError(t, s.Set("default/0/a/b/c", 1))
+	v, err := s.Get("default/0/a/b/c")
+	assert.NoError(t, err)
+	assert.Exactly(t, 1, v)
+
+	assert.NoError(t, s.Set("default/0/a/b/c", nil))
+	_, err = s.Get("default/0/a/b/c")
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+}
+
+func TestJSONStorage(t *testing.T) {
+	s, err := config.NewJSONStorage(strings.NewReader(storageJSON))
+	assert.NoError(t, err)
+	keys, err := s.AllKeys()
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"default/0/a/b/c", "default/0/web/secure/base_url", "stores/2/web/secure/base_url"}, keys)
+	v, err := s.Get("stores/2/web/secure/base_url")
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://corestore.ch/", v)
+	assert.Exactly(t, config.ErrStorageReadOnly, s.Set("stores/2/web/secure/base_url", "x"))
+
+	_, err = config.NewJSONStorage(strings.NewReader(`{"galaxy": {"0": {"a/b/c": 1}}}`))
+	assert.Error(t, err)
+	_, err = config.NewJSONStorage(strings.NewReader(`{"stores": {"x": {"a/b/c": 1}}}`))
+	assert.Error(t, err)
+}
+
+func TestYAMLStorage(t *testing.T) {
+	s, err := config.NewYAMLStorage(strings.NewReader(storageYAML))
+	assert.NoError(t, err)
+	m := config.NewManager(config.SetManagerStorage(s))
+	assert.Exactly(t, "https://corestore.de/", m.GetString(config.Path("web/secure/base_url"), config.ScopeWebsite(config.ScopeID(1))))
+	assert.Exactly(t, "https://corestore.io/", m.GetString(config.Path("web/secure/base_url"), config.ScopeWebsite(config.ScopeID(2))))
+	assert.Exactly(t, []string{"USD", "EUR"}, m.GetStringSlice(config.Path("currency/options/allow")))
+	assert.EqualError(t, m.Write(config.Path("a/b/c"), config.Value(1)), config.ErrStorageReadOnly.Error())
+}
+
+func TestFileStorage(t *testing.T) {
+	f, err := ioutil.TempFile("", "csfw_config_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(storageJSON)
+	f.Close()
+
+	_, err = config.NewFileStorage(f.Name())
+	assert.EqualError(t, err, config.ErrFileFormat.Error())
+
+	jsonFile := f.Name() + ".json"
+	assert.NoError(t, os.Rename(f.Name(), jsonFile))
+	defer os.Remove(jsonFile)
+	s, err := config.NewFileStorage(jsonFile)
+	assert.NoError(t, err)
+	v, err := s.Get("default/0/a/b/c")
+	assert.NoError(t, err)
+	assert.Exactly(t, 1.0, v)
+}
+
+func TestLayeredStorage(t *testing.T) {
+	file, err := config.NewJSONStorage(strings.NewReader(storageJSON))
+	assert.NoError(t, err)
+	mem := config.NewMemoryStorage()
+	assert.NoError(t, mem.Set("stores/3/web/secure/base_url", "https://corestore.at/"))
+
+	m := config.NewManager(config.SetManagerStorage(config.NewLayeredStorage(file, mem)))
+	m.ApplyDefaults(config.NewConfiguration(
+		&config.Section{
+			ID: "a",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "b",
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `a/b/c`,
+							ID:      "c",
+							Default: 2,
+						},
+						&config.Field{
+							// Path: `a/b/d`,
+							ID:      "d",
+							Default: 4,
+						},
+					},
+				},
+			},
+		},
+	))
+
+	// file > memory > defaults
+	assert.Exactly(t, 1, m.GetInt(config.Path("a/b/c")))
+	assert.Exactly(t, 4, m.GetInt(config.Path("a/b/d")))
+	assert.Exactly(t, "https://corestore.ch/", m.GetString(config.Path("web/secure/base_url"), config.ScopeStore(config.ScopeID(2))))
+	assert.Exactly(t, "https://corestore.at/", m.GetString(config.Path("web/secure/base_url"), config.ScopeStore(config.ScopeID(3))))
+
+	// the file layer contains the key and would hide the written value
+	assert.EqualError(t, m.Write(config.Path("a/b/c"), config.Value(3)), config.ErrKeyOverridden.Error())
+	assert.Exactly(t, 1, m.GetInt(config.Path("a/b/c")))
+	_, err = mem.Get("default/0/a/b/c")
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+	assert.NoError(t, m.Write(config.Path("a/b/d"), config.Value(5)))
+	assert.Exactly(t, 5, m.GetInt(config.Path("a/b/d")))
+	v, err := mem.Get("default/0/a/b/d")
+	assert.NoError(t, err)
+	assert.Exactly(t, 5, v)
+
+	assert.Exactly(t, config.ErrStorageReadOnly, config.NewLayeredStorage(file).Set("a", 1))
+	assert.Contains(t, m.AllKeys(), "stores/3/web/secure/base_url")
+	assert.Contains(t, m.AllKeys(), "default/0/a/b/d")
+}
+
+func TestDBStorageCoreConfigDataDB(t *testing.T) {
+	db := csdb.MustConnectTest()
+	defer db.Close()
+	tx, err := dbr.NewConnection(db, nil).NewSession(nil).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	s := config.NewDBStorage(tx)
+	assert.NoError(t, s.Load())
+	assert.NoError(t, s.Set("stores/1/tax/display/type", 2))
+	assert.EqualError(t, s.Set("stores/x/tax/display/type", 2), config.ErrInvalidKey.Error())
+
+	s2 := config.NewDBStorage(tx)
+	assert.NoError(t, s2.Load())
+	v, err := s2.Get("stores/1/tax/display/type")
+	assert.NoError(t, err)
+	assert.Exactly(t, "2", v)
+
+	// nil deletes the row
+	assert.NoError(t, s.Set("stores/1/tax/display/type", nil))
+	_, err = s.Get("stores/1/tax/display/type")
+	assert.Exactly(t, config.ErrKeyNotFound, err)
+	assert.False(t, selectStoreValue(t, tx, "tax/display/type").Valid)
+}
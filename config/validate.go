@@ -0,0 +1,176 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/utils/cast"
+	"github.com/corestoreio/csfw/utils/log"
+)
+
+// ErrValueNotAllowed gets returned in strict mode when a value does not match
+// the FieldType, the type of the Default value or the SourceModel options of a Field.
+var ErrValueNotAllowed = errors.New("Value not allowed for this path")
+
+// scopeAllowed checks if the ScopePerm of the Field contains the scope of the
+// argument. A Field without a ScopePerm is allowed in all scopes.
+func scopeAllowed(f *Field, a *arg) bool {
+	return permAllowed(f.Scope, a)
+}
+
+// permAllowed checks if the ScopePerm of a Section, Group or Field contains
+// the scope of the argument. An empty ScopePerm allows all scopes.
+func permAllowed(p ScopePerm, a *arg) bool {
+	if p == 0 {
+		return true
+	}
+	sg := a.s
+	if a.isDefault() {
+		sg = ScopeDefaultID
+	}
+	return p.Has(sg)
+}
+
+// validate checks the argument against the SectionSlice of the Manager.
+// Returns ErrPathEmpty, ErrFieldNotFound, ErrScopeNotAllowed or ErrValueNotAllowed.
+// A nil value is always allowed because it removes a value.
+func (m *Manager) validate(a *arg) error {
+	if a.p == "" {
+		return ErrPathEmpty
+	}
+	f, err := m.fields().FindFieldByPath(a.p)
+	if err != nil {
+		if log.IsDebug() {
+			log.Debug("Manager=validate", "path", a.p, "err", err)
+		}
+		return ErrFieldNotFound
+	}
+	if false == scopeAllowed(f, a) {
+		if log.IsDebug() {
+			log.Debug("Manager=validate", "path", a.p, "scope", a.s, "perm", f.Scope.Human())
+		}
+		return ErrScopeNotAllowed
+	}
+	if a.v == nil {
+		return nil
+	}
+	if false == validValue(f, newModelConstructor(m, a), a.v) {
+		if log.IsDebug() {
+			log.Debug("Manager=validate", "path", a.p, "val", a.v)
+		}
+		return ErrValueNotAllowed
+	}
+	return nil
+}
+
+// validValue checks the value against the FieldType, the type of the Default
+// value and, for select fields, against the options of the SourceModel.
+func validValue(f *Field, mc ModelConstructor, v interface{}) bool {
+	var ft FieldType
+	if f.Type != nil {
+		ft = f.Type.Type()
+	}
+
+	switch ft {
+	case TypeSelect, TypeMultiselect:
+		vals, err := cast.ToStringSliceE(v)
+		if s, ok := v.(string); ok {
+			vals, err = strings.Split(s, ","), nil
+			switch {
+			case s == "":
+				vals = nil
+			case ft == TypeSelect:
+				vals = []string{s}
+			}
+		}
+		if err != nil {
+			return false
+		}
+		return inOptions(f.SourceModel, mc, vals)
+	case TypeTime:
+		return validTime(v)
+	case TypeText, TypeTextarea, TypeHidden, TypeObscure:
+		if _, err := cast.ToStringE(v); err != nil {
+			return false
+		}
+	}
+
+	var err error
+	switch f.Default.(type) {
+	case bool:
+		_, err = cast.ToBoolE(v)
+	case int, int64:
+		_, err = cast.ToIntE(v)
+	case float64:
+		_, err = cast.ToFloat64E(v)
+	}
+	return err == nil
+}
+
+// validTime checks a time in the format hour,minute,second like the three
+// selects of the HTML form, e.g. "23,59,00". The hour must be within 0-23, the
+// minute and the second within 0-59.
+func validTime(v interface{}) bool {
+	var parts []string
+	var err error
+	switch vt := v.(type) {
+	case string:
+		parts = strings.Split(vt, ",")
+	case []int:
+		for _, i := range vt {
+			parts = append(parts, strconv.Itoa(i))
+		}
+	default:
+		parts, err = cast.ToStringSliceE(v)
+	}
+	if err != nil || len(parts) != 3 {
+		return false
+	}
+	for i, max := range [...]int{23, 59, 59} {
+		n, err := strconv.Atoi(strings.TrimSpace(parts[i]))
+		if err != nil || n < 0 || n > max {
+			return false
+		}
+	}
+	return true
+}
+
+// inOptions checks if all values are available in the options of the
+// SourceModel. Without a SourceModel or options all values are allowed.
+func inOptions(sm FieldSourceModeller, mc ModelConstructor, vals []string) bool {
+	if sm == nil {
+		return true
+	}
+	opts := sourceOptions(sm, mc)
+	if len(opts) == 0 {
+		return true
+	}
+	for _, val := range vals {
+		found := false
+		for _, o := range opts {
+			if o.Value == strings.TrimSpace(val) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
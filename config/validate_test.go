@@ -0,0 +1,104 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type validateSourceModel config.ValueLabelSlice
+
+func (vs validateSourceModel) Options() config.ValueLabelSlice {
+	return config.ValueLabelSlice(vs)
+}
+
+func TestManagerStrictWrite(t *testing.T) {
+	currencies := validateSourceModel{{Value: "CHF", Label: "Franc"}, {Value: "EUR", Label: "Euro"}, {Value: "USD", Label: "Dollar"}}
+	m := config.NewManager(config.SetManagerStrict(append(writerDBConfiguration, &config.Section{
+		ID: "currency",
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID: "options",
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `currency/options/base`,
+						ID:          "base",
+						Type:        config.TypeSelect,
+						Scope:       config.ScopePermAll,
+						SourceModel: currencies,
+					},
+					&config.Field{
+						// Path: `currency/options/allow`,
+						ID:          "allow",
+						Type:        config.TypeMultiselect,
+						Scope:       config.ScopePermAll,
+						SourceModel: currencies,
+					},
+					&config.Field{
+						// Path: `currency/options/import_time`,
+						ID:    "import_time",
+						Type:  config.TypeTime,
+						Scope: config.ScopePermAll,
+					},
+				},
+			},
+		},
+	})))
+
+	tests := []struct {
+		have    []config.ArgFunc
+		wantErr error
+	}{
+		{[]config.ArgFunc{config.Value(3)}, config.ErrPathEmpty},
+		{[]config.ArgFunc{config.Path("a/b/c"), config.Value(3)}, config.ErrFieldNotFound},
+		{[]config.ArgFunc{config.Path("tax/classes/default_product_tax_class"), config.Value(3)}, nil},
+		{[]config.ArgFunc{config.Path("tax/classes/default_product_tax_class"), config.Value(3), config.ScopeStore(config.ScopeID(1))}, config.ErrScopeNotAllowed},
+		{[]config.ArgFunc{config.Path("tax/classes/default_product_tax_class"), config.Value("x")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("tax/display/type"), config.Value("2"), config.ScopeStore(config.ScopeID(1))}, nil},
+		{[]config.ArgFunc{config.Path("tax/display/type"), config.Value(nil), config.ScopeStore(config.ScopeID(1))}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/base"), config.Value("EUR"), config.ScopeWebsite(config.ScopeID(1))}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/base"), config.Value("EUR,USD")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("currency/options/allow"), config.Value("EUR,USD")}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/allow"), config.Value([]string{"CHF", "USD"})}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/allow"), config.Value("EUR,XXX")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("currency/options/allow"), config.Value("")}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("23,59,00")}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("0, 5, 9")}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value([]int{12, 30, 59})}, nil},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("24,00,00")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("12,60,00")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("12,00,60")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("-1,00,00")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("12,00")}, config.ErrValueNotAllowed},
+		{[]config.ArgFunc{config.Path("currency/options/import_time"), config.Value("ab,00,00")}, config.ErrValueNotAllowed},
+	}
+	for i, test := range tests {
+		err := m.Write(test.have...)
+		if test.wantErr == nil {
+			assert.NoError(t, err, "Index %d", i)
+			continue
+		}
+		assert.EqualError(t, err, test.wantErr.Error(), "Index %d", i)
+	}
+	assert.Exactly(t, 3, m.GetInt(config.Path("tax/classes/default_product_tax_class")))
+}
+
+func TestManagerNonStrictWrite(t *testing.T) {
+	m := config.NewManager()
+	assert.NoError(t, m.Write(config.Path("a/b/c"), config.Value(3), config.ScopeStore(config.ScopeID(1))))
+}
@@ -14,6 +14,8 @@
 
 package config
 
+import "github.com/juju/errgo"
+
 const (
 	VisibleAbsent Visible = iota // must start from 0
 	VisibleYes
@@ -27,7 +29,7 @@ type (
 	Visible uint8
 )
 
-// MarshalJSON implements marshaling into a human readable string. @todo UnMarshal
+// MarshalJSON implements marshaling into a human readable string.
 func (v Visible) MarshalJSON() ([]byte, error) {
 	switch v {
 	case VisibleAbsent:
@@ -37,3 +39,18 @@ func (v Visible) MarshalJSON() ([]byte, error) {
 	}
 	return []byte(`false`), nil
 }
+
+// UnmarshalJSON implements unmarshaling of true, false or null.
+func (v *Visible) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "null":
+		*v = VisibleAbsent
+	case "true":
+		*v = VisibleYes
+	case "false":
+		*v = VisibleNo
+	default:
+		return errgo.Newf("Cannot unmarshal %q into Visible", data)
+	}
+	return nil
+}
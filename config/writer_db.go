@@ -0,0 +1,310 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/utils/cast"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+var (
+	// ErrPathEmpty gets returned when an argument list does not contain a Path().
+	ErrPathEmpty = errors.New("Path is empty")
+	// ErrScopeNotAllowed gets returned when the ScopePerm of a Field does not
+	// include the scope of the value to be written.
+	ErrScopeNotAllowed = errors.New("Scope not allowed for this path")
+	// ErrReaderMissing gets returned when the BackendModel of a Field needs
+	// a Reader but none has been set, see SetDBWriterReader().
+	ErrReaderMissing = errors.New("Reader is missing")
+	// ErrTxMissing gets returned by DBWriter.Commit() and DBWriter.Rollback()
+	// if the DBWriter does not write within a dbr.Tx.
+	ErrTxMissing = errors.New("Transaction is missing")
+)
+
+type (
+	// DBWriter persists configuration values in the table core_config_data. Each
+	// value will be upserted by its unique key scope, scope_id and path.
+	// Use a dbr.Tx as dbr.SessionRunner if you need several writes to be atomic
+	// and finish it with Commit() or Rollback() of the DBWriter.
+	DBWriter struct {
+		dbrSess dbr.SessionRunner
+		// bubble writes website and store values also into the default scope.
+		bubble bool
+		// sections contains the merged PackageConfiguration to look up the ScopePerm
+		// and the BackendModel of a Field. Can be nil then all scopes are allowed.
+		sections SectionSlice
+		// w receives the same arguments after a successful write to the database,
+		// mostly the Manager. Can be nil.
+		w Writer
+		// r gets passed to the BackendModel of a Field as ConfigReader.
+		r Reader
+		// mu protects pending
+		mu sync.Mutex
+		// pending contains the arguments for w which will be written after
+		// Commit() if dbrSess is a dbr.Tx.
+		pending [][]ArgFunc
+	}
+
+	// DBWriterOption option func for NewDBWriter()
+	DBWriterOption func(*DBWriter)
+)
+
+var _ Writer = (*DBWriter)(nil)
+
+// SetDBWriterSections sets the merged SectionSlice of all packages to check
+// the ScopePerm of a Field before writing. Optional.
+func SetDBWriterSections(ss SectionSlice) DBWriterOption {
+	return func(dw *DBWriter) { dw.sections = ss }
+}
+
+// SetDBWriterManager sets a Writer, mostly the Manager, which receives the
+// same value after it has been successfully stored in the database. Within a
+// dbr.Tx the values will be written after DBWriter.Commit(). A Manager checks
+// its strict mode and its overrides before the database will be touched. If w
+// is also a Reader it will be used as Reader, see SetDBWriterReader(). Optional.
+func SetDBWriterManager(w Writer) DBWriterOption {
+	return func(dw *DBWriter) {
+		dw.w = w
+		if r, ok := w.(Reader); ok && dw.r == nil {
+			dw.r = r
+		}
+	}
+}
+
+// SetDBWriterReader sets the Reader which the BackendModel of a Field receives
+// as ConfigReader. Required if one of the SectionSlices contains a BackendModel.
+func SetDBWriterReader(r Reader) DBWriterOption {
+	return func(dw *DBWriter) { dw.r = r }
+}
+
+// SetDBWriterBubble enables writing a website or store value also into the
+// default scope like Manager.Write does, unless NoBubble() has been set.
+// Disabled by default because one store value would overwrite the default
+// of all other stores.
+func SetDBWriterBubble(b bool) DBWriterOption {
+	return func(dw *DBWriter) { dw.bubble = b }
+}
+
+// NewDBWriter creates a new Writer which persists the values in table core_config_data.
+func NewDBWriter(dbrSess dbr.SessionRunner, opts ...DBWriterOption) *DBWriter {
+	dw := &DBWriter{
+		dbrSess: dbrSess,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(dw)
+		}
+	}
+	return dw
+}
+
+// Write upserts a value into core_config_data. A nil value deletes the row
+// like Storager.Set removes the key. The order of the arguments doesn't matter. Only the scope of the arguments will be written, the default
+// scope only if SetDBWriterBubble() has been enabled and NoBubble() has not been set.
+// Returns ErrScopeNotAllowed if the ScopePerm of the Field does not contain the scope,
+// ErrReaderMissing, the error of the strict mode or ErrKeyOverridden of the
+// Manager or the error of the BeforeSave() hook of the BackendModel. All
+// checks run before the database will be touched.
+//
+//	Default Scope: Write(config.Path("currency", "option", "base"), config.Value("USD"))
+//	Website Scope: Write(config.Path("currency", "option", "base"), config.Value("EUR"), config.ScopeWebsite(w))
+//	Store   Scope: Write(config.Path("currency", "option", "base"), config.Value("CHF"), config.ScopeStore(s))
+func (dw *DBWriter) Write(o ...ArgFunc) error {
+	a := newArg(o...)
+	if a.p == "" {
+		return ErrPathEmpty
+	}
+	if err := dw.checkScope(a); err != nil {
+		return err
+	}
+
+	bubble := dw.bubble && a.isBubbling()
+	if false == bubble {
+		// keep the Writer in sync with the database
+		o = append(o, NoBubble())
+	}
+	if m, ok := dw.w.(*Manager); ok {
+		if err := m.checkWrite(newArg(o...)); err != nil {
+			return err
+		}
+	}
+
+	if dw.r == nil && false == a.nh && backendModel(dw.sections, a.p) != nil {
+		return ErrReaderMissing
+	}
+	v, err := beforeSave(dw.sections, dw.r, a)
+	if err != nil {
+		return err
+	}
+	val, err := toDBValue(v)
+	if err != nil {
+		return errgo.Mask(err)
+	}
+
+	if bubble && false == a.isDefault() {
+		if log.IsDebug() {
+			log.Debug("DBWriter=Write", "path", a.scopePathDefault(), "bubble", bubble, "val", val)
+		}
+		if err := writeCoreConfigData(dw.dbrSess, ScopeRangeDefault, 0, a.p, val); err != nil {
+			return errgo.Mask(err)
+		}
+	}
+
+	if log.IsDebug() {
+		log.Debug("DBWriter=Write", "path", a.scopePath(), "val", val)
+	}
+	if err := writeCoreConfigData(dw.dbrSess, a.scopeRange(), a.scopeIDInt64(), a.p, val); err != nil {
+		return errgo.Mask(err)
+	}
+
+	if dw.w == nil {
+		return nil
+	}
+	// the value has already passed the BackendModel
+	o = append(o, Value(v), RawValue())
+	if _, ok := dw.dbrSess.(*dbr.Tx); ok {
+		dw.mu.Lock()
+		dw.pending = append(dw.pending, o)
+		dw.mu.Unlock()
+		return nil
+	}
+	return dw.w.Write(o...)
+}
+
+// Commit commits the dbr.Tx and writes afterwards all values of the
+// transaction into the Writer of SetDBWriterManager(). Returns ErrTxMissing if
+// the DBWriter does not write within a dbr.Tx.
+func (dw *DBWriter) Commit() error {
+	tx, ok := dw.dbrSess.(*dbr.Tx)
+	if !ok {
+		return ErrTxMissing
+	}
+	dw.mu.Lock()
+	pending := dw.pending
+	dw.pending = nil
+	dw.mu.Unlock()
+
+	if err := tx.Commit(); err != nil {
+		return errgo.Mask(err)
+	}
+	var wErr error
+	for _, o := range pending {
+		if err := dw.w.Write(o...); err != nil && wErr == nil {
+			wErr = err
+		}
+	}
+	return wErr
+}
+
+// Rollback rolls the dbr.Tx back and discards all values of the transaction,
+// the Writer of SetDBWriterManager() stays untouched. Returns ErrTxMissing if
+// the DBWriter does not write within a dbr.Tx.
+func (dw *DBWriter) Rollback() error {
+	tx, ok := dw.dbrSess.(*dbr.Tx)
+	if !ok {
+		return ErrTxMissing
+	}
+	dw.mu.Lock()
+	dw.pending = nil
+	dw.mu.Unlock()
+	return errgo.Mask(tx.Rollback())
+}
+
+// checkScope verifies the scope of the argument against the ScopePerm of the
+// Field. Fields which cannot be found or without a ScopePerm are allowed in all scopes.
+func (dw *DBWriter) checkScope(a *arg) error {
+	if dw.sections == nil {
+		return nil
+	}
+	f, err := dw.sections.FindFieldByPath(a.p)
+	if err != nil {
+		return nil
+	}
+	if false == scopeAllowed(f, a) {
+		if log.IsDebug() {
+			log.Debug("DBWriter=checkScope", "path", a.p, "scope", a.s, "perm", f.Scope.Human())
+		}
+		return ErrScopeNotAllowed
+	}
+	return nil
+}
+
+// writeCoreConfigData upserts a valid value or deletes the row of a NULL value.
+func writeCoreConfigData(dbrSess dbr.SessionRunner, scope string, scopeID int64, path string, val dbr.NullString) error {
+	if val.Valid {
+		return upsertCoreConfigData(dbrSess, scope, scopeID, path, val)
+	}
+	_, err := dbrSess.
+		DeleteFrom(TableCollection.Name(TableIndexCoreConfigData)).
+		Where("scope = ?", scope).
+		Where("scope_id = ?", scopeID).
+		Where("path = ?", path).
+		Exec()
+	return errgo.Mask(err)
+}
+
+// upsertCoreConfigData inserts a new row into core_config_data or updates the
+// value of the existing row with the same unique key scope, scope_id and path
+// in one statement.
+func upsertCoreConfigData(dbrSess dbr.SessionRunner, scope string, scopeID int64, path string, val dbr.NullString) error {
+	_, err := dbrSess.UpdateBySql(
+		"INSERT INTO `"+TableCollection.Name(TableIndexCoreConfigData)+"` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)",
+		scope, scopeID, path, val,
+	).Exec()
+	return errgo.Mask(err)
+}
+
+// toDBValue converts a value into the string representation used in the
+// column core_config_data.value. Booleans become 1 or 0 and slices will
+// be joined by comma. nil becomes NULL.
+func toDBValue(v interface{}) (dbr.NullString, error) {
+	var s string
+	switch vt := v.(type) {
+	case nil:
+		return dbr.NullString{}, nil
+	case bool:
+		s = "0"
+		if vt {
+			s = "1"
+		}
+	case int64:
+		s = strconv.FormatInt(vt, 10)
+	case []string:
+		s = strings.Join(vt, ",")
+	case []int:
+		is := make([]string, len(vt))
+		for i, iv := range vt {
+			is[i] = strconv.Itoa(iv)
+		}
+		s = strings.Join(is, ",")
+	case time.Time:
+		s = vt.Format("2006-01-02 15:04:05")
+	default:
+		var err error
+		if s, err = cast.ToStringE(v); err != nil {
+			return dbr.NullString{}, err
+		}
+	}
+	return dbr.NullString{NullString: sql.NullString{String: s, Valid: true}}, nil
+}
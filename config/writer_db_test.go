@@ -0,0 +1,186 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/stretchr/testify/assert"
+)
+
+var writerDBConfiguration = config.NewConfiguration(
+	&config.Section{
+		ID: "tax",
+		Groups: config.GroupSlice{
+			&config.Group{
+				ID: "classes",
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `tax/classes/default_product_tax_class`,
+						ID:      "default_product_tax_class",
+						Scope:   config.NewScopePerm(config.ScopeDefaultID),
+						Default: 2,
+					},
+				},
+			},
+			&config.Group{
+				ID: "display",
+				Fields: config.FieldSlice{
+					&config.Field{
+						// Path: `tax/display/type`,
+						ID:      "type",
+						Scope:   config.ScopePermAll,
+						Default: 1,
+					},
+				},
+			},
+		},
+	},
+)
+
+func TestDBWriterCheckScope(t *testing.T) {
+	// the database connection will never be touched because all writes fail before
+	sess := dbr.NewConnection(nil, nil).NewSession(nil)
+	dw := config.NewDBWriter(sess, config.SetDBWriterSections(writerDBConfiguration))
+
+	tests := []struct {
+		have    []config.ArgFunc
+		wantErr error
+	}{
+		{[]config.ArgFunc{config.Value(3)}, config.ErrPathEmpty},
+		{[]config.ArgFunc{config.Path("tax/classes/default_product_tax_class"), config.Value(3), config.ScopeStore(config.ScopeID(1))}, config.ErrScopeNotAllowed},
+		{[]config.ArgFunc{config.Path("tax/classes/default_product_tax_class"), config.Value(3), config.ScopeWebsite(config.ScopeID(1))}, config.ErrScopeNotAllowed},
+	}
+	for i, test := range tests {
+		assert.EqualError(t, dw.Write(test.have...), test.wantErr.Error(), "Index %d", i)
+	}
+}
+
+func TestDBWriterReaderMissing(t *testing.T) {
+	ss := config.NewConfiguration(
+		&config.Section{
+			ID: "payment",
+			Groups: config.GroupSlice{
+				&config.Group{
+					ID: "paypal",
+					Fields: config.FieldSlice{
+						&config.Field{
+							// Path: `payment/paypal/api_password`,
+							ID:           "api_password",
+							Type:         config.TypeObscure,
+							BackendModel: config.NewBackendEncryption(),
+						},
+					},
+				},
+			},
+		},
+	)
+	sess := dbr.NewConnection(nil, nil).NewSession(nil)
+	dw := config.NewDBWriter(sess, config.SetDBWriterSections(ss))
+	assert.EqualError(t, dw.Write(config.Path("payment/paypal/api_password"), config.Value("s3cr3t")), config.ErrReaderMissing.Error())
+
+	// the BackendModel fails before the database will be touched
+	dw = config.NewDBWriter(sess, config.SetDBWriterSections(ss), config.SetDBWriterReader(config.NewManager()))
+	assert.EqualError(t, dw.Write(config.Path("payment/paypal/api_password"), config.Value("s3cr3t")), config.ErrEncryptionKeyMissing.Error())
+}
+
+func TestDBWriterCheckManager(t *testing.T) {
+	es, err := config.NewEnvStorage([]string{"CS_CONFIG__TAX__DISPLAY__TYPE=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := config.NewManager(config.SetManagerStrict(writerDBConfiguration), config.SetManagerEnvOverrides(es))
+	// the database connection will never be touched because all writes fail before
+	sess := dbr.NewConnection(nil, nil).NewSession(nil)
+	dw := config.NewDBWriter(sess, config.SetDBWriterManager(m))
+
+	tests := []struct {
+		have    []config.ArgFunc
+		wantErr error
+	}{
+		{[]config.ArgFunc{config.Path("tax/display/type"), config.Value(3)}, config.ErrKeyOverridden},
+		{[]config.ArgFunc{config.Path("tax/display/unknown"), config.Value(3)}, config.ErrFieldNotFound},
+		{[]config.ArgFunc{config.Path("tax/classes/default_product_tax_class"), config.Value(3), config.ScopeStore(config.ScopeID(1))}, config.ErrScopeNotAllowed},
+	}
+	for i, test := range tests {
+		assert.EqualError(t, dw.Write(test.have...), test.wantErr.Error(), "Index %d", i)
+	}
+	assert.EqualError(t, dw.Commit(), config.ErrTxMissing.Error())
+	assert.EqualError(t, dw.Rollback(), config.ErrTxMissing.Error())
+}
+
+// selectStoreValue returns the value of store 1 from core_config_data or NULL.
+func selectStoreValue(t *testing.T, dbrSess dbr.SessionRunner, path string) dbr.NullString {
+	var val dbr.NullString
+	err := dbrSess.Select("value").
+		From(config.TableCollection.Name(config.TableIndexCoreConfigData)).
+		Where("scope = ?", config.ScopeRangeStores).
+		Where("scope_id = ?", 1).
+		Where("path = ?", path).
+		LoadValue(&val)
+	if err != nil && err != dbr.ErrNotFound {
+		t.Fatal(err)
+	}
+	return val
+}
+
+func TestDBWriterWriteCoreConfigData(t *testing.T) {
+	db := csdb.MustConnectTest()
+	defer db.Close()
+	sess := dbr.NewConnection(db, nil).NewSession(nil)
+	defer config.NewDBWriter(sess).Write(config.Path("tax/display/type"), config.Value(nil), config.ScopeStore(config.ScopeID(1)))
+	tx, err := sess.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	m := config.NewManager()
+	dw := config.NewDBWriter(tx, config.SetDBWriterSections(writerDBConfiguration), config.SetDBWriterManager(m))
+
+	assert.NoError(t, dw.Write(config.Path("tax/display/type"), config.Value(2), config.ScopeStore(config.ScopeID(1))))
+	assert.NoError(t, dw.Write(config.Path("tax/display/type"), config.Value(3), config.ScopeStore(config.ScopeID(1))))
+	assert.Exactly(t, "3", selectStoreValue(t, tx, "tax/display/type").String)
+	// the Manager receives the values after the commit
+	assert.False(t, m.IsSet(config.Path("tax/display/type"), config.ScopeStore(config.ScopeID(1))))
+
+	assert.NoError(t, dw.Commit())
+	assert.Exactly(t, 3, m.GetInt(config.Path("tax/display/type"), config.ScopeStore(config.ScopeID(1))))
+	// the default scope stays untouched without SetDBWriterBubble()
+	_, _, err = m.Lookup(config.Path("tax/display/type"))
+	assert.EqualError(t, err, config.ErrKeyNotFound.Error())
+}
+
+func TestDBWriterRollbackDB(t *testing.T) {
+	db := csdb.MustConnectTest()
+	defer db.Close()
+	sess := dbr.NewConnection(db, nil).NewSession(nil)
+	tx, err := sess.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	m := config.NewManager()
+	dw := config.NewDBWriter(tx, config.SetDBWriterSections(writerDBConfiguration), config.SetDBWriterManager(m))
+	assert.NoError(t, dw.Write(config.Path("tax/display/type"), config.Value(2), config.ScopeStore(config.ScopeID(1))))
+	assert.NoError(t, dw.Rollback())
+
+	assert.False(t, m.IsSet(config.Path("tax/display/type"), config.ScopeStore(config.ScopeID(1))))
+	assert.False(t, selectStoreValue(t, sess, "tax/display/type").Valid)
+}
@@ -0,0 +1,58 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customer
+
+import (
+	"strconv"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/utils/log"
+)
+
+// GroupNotLoggedInID is the ID of the customer group NOT LOGGED IN.
+const GroupNotLoggedInID int64 = 0
+
+// SourceGroup lists all customer groups for logged in customers from the table
+// customer_group. Magento\Customer\Model\Config\Source\Group
+type SourceGroup struct {
+	dbrSess dbr.SessionRunner
+}
+
+var _ config.FieldSourceModeller = (*SourceGroup)(nil)
+
+// NewSourceGroup creates a new option for the customer groups.
+func NewSourceGroup(dbrSess dbr.SessionRunner) *SourceGroup {
+	return &SourceGroup{dbrSess: dbrSess}
+}
+
+// Options returns the group ID as value and the group code as label. The
+// group NOT LOGGED IN will be skipped. Errors will be logged.
+func (sg *SourceGroup) Options() config.ValueLabelSlice {
+	var tgs TableGroupSlice
+	if _, err := csdb.LoadSlice(sg.dbrSess, TableCollection, TableIndexGroup, &tgs); err != nil {
+		log.Error("customer.SourceGroup.Options.LoadSlice", "err", err)
+		return nil
+	}
+	vls := make(config.ValueLabelSlice, 0, len(tgs))
+	for _, tg := range tgs {
+		if tg == nil || tg.CustomerGroupID == GroupNotLoggedInID {
+			continue
+		}
+		vls = append(vls, config.ValueLabel{Value: strconv.FormatInt(tg.CustomerGroupID, 10), Label: tg.CustomerGroupCode})
+	}
+	return vls
+}
@@ -0,0 +1,173 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory
+
+import (
+	"errors"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/utils/cast"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+var (
+	// ErrCurrencyNotAllowed gets returned when the base or default currency
+	// is not in the allowed currencies.
+	ErrCurrencyNotAllowed = errors.New("Currency is not in the allowed currencies")
+	// ErrCurrencyNotInstalled gets returned when a currency is not installed.
+	ErrCurrencyNotInstalled = errors.New("Currency is not installed")
+)
+
+type (
+	// BackendCurrencyBase used in Path: `currency/options/base` and
+	// `currency/options/default`. Checks that the currency is installed and allowed.
+	// Magento\Config\Model\Config\Backend\Currency\Base and DefaultCurrency
+	BackendCurrencyBase struct{}
+
+	// BackendCurrencyAllow used in Path: `currency/options/allow`. Checks that
+	// all currencies are installed and that the base and the default currency
+	// are allowed.
+	// Magento\Config\Model\Config\Backend\Currency\Allow
+	BackendCurrencyAllow struct{}
+)
+
+var (
+	_ config.FieldBackendModeller = (*BackendCurrencyBase)(nil)
+	_ config.FieldBackendModeller = (*BackendCurrencyAllow)(nil)
+)
+
+// NewBackendCurrencyBase creates a new backend model for the base currency.
+func NewBackendCurrencyBase() *BackendCurrencyBase {
+	return &BackendCurrencyBase{}
+}
+
+// BeforeSave returns ErrCurrencyNotInstalled or ErrCurrencyNotAllowed if the
+// currency cannot be used in the scope or in one of the scopes below.
+func (bcb *BackendCurrencyBase) BeforeSave(mc config.ModelConstructor, v interface{}) (interface{}, error) {
+	cur, err := cast.ToStringE(v)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	if false == inCurrencies(cur, InstalledCurrencies(mc.ConfigReader)) {
+		return nil, ErrCurrencyNotInstalled
+	}
+	if err := validateCurrencies(mc, []string{cur}); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// AfterLoad noop
+func (bcb *BackendCurrencyBase) AfterLoad(_ config.ModelConstructor, v interface{}) (interface{}, error) {
+	return v, nil
+}
+
+// NewBackendCurrencyAllow creates a new backend model for the allowed currencies.
+func NewBackendCurrencyAllow() *BackendCurrencyAllow {
+	return &BackendCurrencyAllow{}
+}
+
+// BeforeSave returns ErrCurrencyNotInstalled if a currency is not installed or
+// ErrCurrencyNotAllowed if the base or default currency of the scope or of one
+// of the scopes below is missing.
+func (bca *BackendCurrencyAllow) BeforeSave(mc config.ModelConstructor, v interface{}) (interface{}, error) {
+	allowed, err := config.ToStringSlice(v, true)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	installed := InstalledCurrencies(mc.ConfigReader)
+	for _, cur := range allowed {
+		if false == inCurrencies(cur, installed) {
+			if log.IsDebug() {
+				log.Debug("BackendCurrencyAllow=BeforeSave", "currency", cur, "installed", installed)
+			}
+			return nil, ErrCurrencyNotInstalled
+		}
+	}
+	if err := validateCurrencies(mc, allowed); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// AfterLoad noop
+func (bca *BackendCurrencyAllow) AfterLoad(_ config.ModelConstructor, v interface{}) (interface{}, error) {
+	return v, nil
+}
+
+// childScoper gets implemented by the config.Manager, see ChildScopes().
+type childScoper interface {
+	ChildScopes(sg config.ScopeGroup, r config.ScopeIDer, paths ...string) []config.ArgFunc
+}
+
+// validateCurrencies checks that the base and the default currency are allowed
+// in the scope of mc and in all scopes below with their own currency settings.
+// The currencies v of mc.Path will be used where the value gets inherited from
+// the scope of mc.
+func validateCurrencies(mc config.ModelConstructor, v []string) error {
+	scopes := []config.ArgFunc{config.Scope(mc.ScopeGroup, mc.Scope)}
+	if cs, ok := mc.ConfigReader.(childScoper); ok {
+		scopes = append(scopes, cs.ChildScopes(mc.ScopeGroup, mc.Scope, PathCurrencyAllow, PathCurrencyBase, PathCurrencyDefault)...)
+	}
+	for _, sa := range scopes {
+		allowed, err := scopedCurrencies(mc, sa, PathCurrencyAllow, v)
+		if err != nil {
+			return err
+		}
+		for _, p := range []string{PathCurrencyBase, PathCurrencyDefault} {
+			cur, err := scopedCurrencies(mc, sa, p, v)
+			if err != nil {
+				return err
+			}
+			if len(cur) > 0 && false == inCurrencies(cur[0], allowed) {
+				if log.IsDebug() {
+					log.Debug("directory.validateCurrencies", "path", p, "currency", cur[0], "allowed", allowed)
+				}
+				return ErrCurrencyNotAllowed
+			}
+		}
+	}
+	return nil
+}
+
+// scopedCurrencies returns the currencies of the path in the scope sa. If the
+// path will be written and sa inherits it from the scope of mc, v gets returned.
+func scopedCurrencies(mc config.ModelConstructor, sa config.ArgFunc, path string, v []string) ([]string, error) {
+	if path == mc.Path {
+		_, sg, err := mc.ConfigReader.Lookup(config.Path(path), sa)
+		switch {
+		case err == config.ErrKeyNotFound, err == nil && sg <= mc.ScopeGroup:
+			return v, nil
+		case err != nil:
+			return nil, errgo.Mask(err)
+		}
+	}
+	cs, err := mc.ConfigReader.StringSlice(config.Path(path), sa)
+	if err == config.ErrKeyNotFound {
+		return nil, nil
+	}
+	return cs, errgo.Mask(err)
+}
+
+// inCurrencies checks if cur is contained in cs.
+func inCurrencies(cur string, cs []string) bool {
+	for _, c := range cs {
+		if c == cur {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/directory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackendCurrency(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(directory.PackageConfiguration)
+	w1 := config.ScopeWebsite(config.ScopeID(1))
+
+	// base currency must be allowed
+	assert.EqualError(t, m.Write(config.Path(directory.PathCurrencyBase), config.Value("CHF"), w1), directory.ErrCurrencyNotAllowed.Error())
+	assert.Exactly(t, "USD", m.GetString(config.Path(directory.PathCurrencyBase), w1))
+	assert.EqualError(t, m.Write(config.Path(directory.PathCurrencyDefault), config.Value("XXX"), w1), directory.ErrCurrencyNotInstalled.Error())
+
+	// allowed currencies must contain the base and default currency
+	assert.EqualError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value("CHF,EUR"), w1, config.NoBubble()), directory.ErrCurrencyNotAllowed.Error())
+	assert.EqualError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value("CHF,USD,XXX"), w1, config.NoBubble()), directory.ErrCurrencyNotInstalled.Error())
+	assert.NoError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value("CHF,USD"), w1, config.NoBubble()))
+
+	assert.NoError(t, m.Write(config.Path(directory.PathCurrencyBase), config.Value("CHF"), w1, config.NoBubble()))
+	assert.Exactly(t, "CHF", m.GetString(config.Path(directory.PathCurrencyBase), w1))
+	assert.Exactly(t, "USD", m.GetString(config.Path(directory.PathCurrencyBase)))
+
+	assert.EqualError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value([]string{"USD"}), w1, config.NoBubble()), directory.ErrCurrencyNotAllowed.Error())
+
+	// the website inherits the default currency but does not allow EUR
+	assert.EqualError(t, m.Write(config.Path(directory.PathCurrencyDefault), config.Value("EUR")), directory.ErrCurrencyNotAllowed.Error())
+	assert.NoError(t, m.Write(config.Path(directory.PathCurrencyDefault), config.Value("USD"), w1, config.NoBubble()))
+	assert.NoError(t, m.Write(config.Path(directory.PathCurrencyDefault), config.Value("EUR")))
+	assert.Exactly(t, "EUR", m.GetString(config.Path(directory.PathCurrencyDefault)))
+	assert.Exactly(t, "USD", m.GetString(config.Path(directory.PathCurrencyDefault), w1))
+
+	// the default allowed currencies must contain the base currency USD but
+	// not the currencies of the website
+	assert.EqualError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value("EUR")), directory.ErrCurrencyNotAllowed.Error())
+	assert.NoError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value("EUR, USD")))
+}
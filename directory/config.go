@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:generate go run ../codegen/configAccessors/main.go -package directory -o config_accessors.go
+
 package directory
 
 import (
@@ -38,6 +40,9 @@ const (
 	PathDefaultTimezone  = "general/locale/timezone"
 )
 
+// currenciesAll contains all ISO 4217 currency codes known to Magento.
+const currenciesAll = `AZN,AZM,AFN,ALL,DZD,AOA,ARS,AMD,AWG,AUD,BSD,BHD,BDT,BBD,BYR,BZD,BMD,BTN,BOB,BAM,BWP,BRL,GBP,BND,BGN,BUK,BIF,KHR,CAD,CVE,CZK,KYD,CLP,CNY,COP,KMF,CDF,CRC,HRK,CUP,DKK,DJF,DOP,XCD,EGP,SVC,GQE,ERN,EEK,ETB,EUR,FKP,FJD,GMD,GEK,GEL,GHS,GIP,GTQ,GNF,GYD,HTG,HNL,HKD,HUF,ISK,INR,IDR,IRR,IQD,ILS,JMD,JPY,JOD,KZT,KES,KWD,KGS,LAK,LVL,LBP,LSL,LRD,LYD,LTL,MOP,MKD,MGA,MWK,MYR,MVR,LSM,MRO,MUR,MXN,MDL,MNT,MAD,MZN,MMK,NAD,NPR,ANG,TRL,TRY,NZD,NIC,NGN,KPW,NOK,OMR,PKR,PAB,PGK,PYG,PEN,PHP,PLN,QAR,RHD,RON,ROL,RUB,RWF,SHP,STD,SAR,RSD,SCR,SLL,SGD,SKK,SBD,SOS,ZAR,KRW,LKR,SDG,SRD,SZL,SEK,CHF,SYP,TWD,TJS,TZS,THB,TOP,TTD,TND,TMM,USD,UGX,UAH,AED,UYU,UZS,VUV,VEB,VEF,VND,CHE,CHW,XOF,XPF,WST,YER,ZMK,ZWD`
+
 // TableCollection handles all tables and its columns. init() in generated Go file will set the value.
 var TableCollection csdb.TableStructureSlice
 
@@ -69,8 +74,8 @@ func init() {
 							Visible:      config.VisibleYes,
 							Scope:        config.NewScopePerm(config.ScopeDefaultID, config.ScopeWebsiteID),
 							Default:      `USD`,
-							BackendModel: nil, // Magento\Config\Model\Config\Backend\Currency\Base
-							SourceModel:  nil, // Magento\Config\Model\Config\Source\Locale\Currency
+							BackendModel: NewBackendCurrencyBase(), // Magento\Config\Model\Config\Backend\Currency\Base
+							SourceModel:  NewSourceCurrency(),      // Magento\Config\Model\Config\Source\Locale\Currency
 						},
 
 						&config.Field{
@@ -83,8 +88,8 @@ func init() {
 							Visible:      config.VisibleYes,
 							Scope:        config.ScopePermAll,
 							Default:      `USD`,
-							BackendModel: nil, // Magento\Config\Model\Config\Backend\Currency\DefaultCurrency
-							SourceModel:  nil, // Magento\Config\Model\Config\Source\Locale\Currency
+							BackendModel: NewBackendCurrencyBase(), // Magento\Config\Model\Config\Backend\Currency\DefaultCurrency
+							SourceModel:  NewSourceCurrency(),      // Magento\Config\Model\Config\Source\Locale\Currency
 						},
 
 						&config.Field{
@@ -97,8 +102,8 @@ func init() {
 							Visible:      config.VisibleYes,
 							Scope:        config.ScopePermAll,
 							Default:      `USD,EUR`,
-							BackendModel: nil, // Magento\Config\Model\Config\Backend\Currency\Allow
-							SourceModel:  nil, // Magento\Config\Model\Config\Source\Locale\Currency
+							BackendModel: NewBackendCurrencyAllow(), // Magento\Config\Model\Config\Backend\Currency\Allow
+							SourceModel:  NewSourceCurrency(),       // Magento\Config\Model\Config\Source\Locale\Currency
 						},
 					},
 				},
@@ -144,7 +149,7 @@ func init() {
 							Scope:        config.ScopePermAll,
 							Default:      false,
 							BackendModel: nil,
-							SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+							SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 						},
 
 						&config.Field{
@@ -254,7 +259,7 @@ func init() {
 							SortOrder:    1,
 							Visible:      config.VisibleYes,
 							Scope:        config.NewScopePerm(config.ScopeDefaultID),
-							Default:      `AZN,AZM,AFN,ALL,DZD,AOA,ARS,AMD,AWG,AUD,BSD,BHD,BDT,BBD,BYR,BZD,BMD,BTN,BOB,BAM,BWP,BRL,GBP,BND,BGN,BUK,BIF,KHR,CAD,CVE,CZK,KYD,CLP,CNY,COP,KMF,CDF,CRC,HRK,CUP,DKK,DJF,DOP,XCD,EGP,SVC,GQE,ERN,EEK,ETB,EUR,FKP,FJD,GMD,GEK,GEL,GHS,GIP,GTQ,GNF,GYD,HTG,HNL,HKD,HUF,ISK,INR,IDR,IRR,IQD,ILS,JMD,JPY,JOD,KZT,KES,KWD,KGS,LAK,LVL,LBP,LSL,LRD,LYD,LTL,MOP,MKD,MGA,MWK,MYR,MVR,LSM,MRO,MUR,MXN,MDL,MNT,MAD,MZN,MMK,NAD,NPR,ANG,TRL,TRY,NZD,NIC,NGN,KPW,NOK,OMR,PKR,PAB,PGK,PYG,PEN,PHP,PLN,QAR,RHD,RON,ROL,RUB,RWF,SHP,STD,SAR,RSD,SCR,SLL,SGD,SKK,SBD,SOS,ZAR,KRW,LKR,SDG,SRD,SZL,SEK,CHF,SYP,TWD,TJS,TZS,THB,TOP,TTD,TND,TMM,USD,UGX,UAH,AED,UYU,UZS,VUV,VEB,VEF,VND,CHE,CHW,XOF,XPF,WST,YER,ZMK,ZWD`,
+							Default:      currenciesAll,
 							BackendModel: nil,                    // Magento\Config\Model\Config\Backend\Locale
 							SourceModel:  NewSourceCurrencyAll(), // Magento\Config\Model\Config\Source\Locale\Currency\All
 						},
@@ -281,7 +286,7 @@ func init() {
 							Scope:        config.NewScopePerm(config.ScopeDefaultID),
 							Default:      `HK,IE,MO,PA,GB`,
 							BackendModel: nil,
-							SourceModel:  nil, // Magento\Directory\Model\Config\Source\Country
+							SourceModel:  NewSourceCountry(), // Magento\Directory\Model\Config\Source\Country
 						},
 					},
 				},
@@ -303,7 +308,7 @@ func init() {
 							Scope:        config.NewScopePerm(config.ScopeDefaultID),
 							Default:      nil,
 							BackendModel: nil,
-							SourceModel:  nil, // Magento\Directory\Model\Config\Source\Country
+							SourceModel:  NewSourceCountry(), // Magento\Directory\Model\Config\Source\Country
 						},
 
 						&config.Field{
@@ -317,7 +322,7 @@ func init() {
 							Scope:        config.NewScopePerm(config.ScopeDefaultID),
 							Default:      nil,
 							BackendModel: nil,
-							SourceModel:  nil, // Magento\Config\Model\Config\Source\Yesno
+							SourceModel:  config.NewSourceYesNo(), // Magento\Config\Model\Config\Source\Yesno
 						},
 					},
 				},
@@ -419,20 +424,22 @@ func init() {
 
 						&config.Field{
 							// Path: `general/locale/code`,
-							ID:      "code",
-							Type:    config.TypeHidden,
-							Visible: config.VisibleNo,
-							Scope:   config.NewScopePerm(config.ScopeDefaultID), // @todo search for that
-							Default: `en_US`,
+							ID:          "code",
+							Type:        config.TypeHidden,
+							Visible:     config.VisibleNo,
+							Scope:       config.NewScopePerm(config.ScopeDefaultID), // @todo search for that
+							SourceModel: NewSourceLocale(),                          // Magento\Config\Model\Config\Source\Locale
+							Default:     `en_US`,
 						},
 
 						&config.Field{
 							// Path: `general/locale/timezone`,
-							ID:      "timezone",
-							Type:    config.TypeHidden,
-							Visible: config.VisibleNo,
-							Scope:   config.NewScopePerm(config.ScopeDefaultID), // @todo search for that
-							Default: `America/Los_Angeles`,
+							ID:          "timezone",
+							Type:        config.TypeHidden,
+							Visible:     config.VisibleNo,
+							Scope:       config.NewScopePerm(config.ScopeDefaultID), // @todo search for that
+							SourceModel: NewSourceTimezone(),                        // Magento\Config\Model\Config\Source\Locale\Timezone
+							Default:     `America/Los_Angeles`,
 						},
 					},
 				},
@@ -25,3 +25,9 @@ type (
 func DefaultCountry(cr config.Reader, r config.ScopeIDer) string {
 	return cr.GetString(config.Path(PathDefaultCountry), config.ScopeStore(r))
 }
+
+// OptionalZipCountries returns the ISO2 country codes which have an optional
+// Zip/Postal code. Store argument is optional.
+func OptionalZipCountries(cr config.Reader, r config.ScopeIDer) []string {
+	return cr.GetStringSlice(config.Path(PathOptionalZipCountries), config.ScopeStore(r))
+}
@@ -23,3 +23,13 @@ import (
 func BaseCurrencyCode(cr config.Reader) (language.Currency, error) {
 	return language.ParseCurrency(cr.GetString(config.Path(PathCurrencyBase)))
 }
+
+// AllowedCurrencies returns the allowed currency codes. Store argument is optional.
+func AllowedCurrencies(cr config.Reader, r config.ScopeIDer) []string {
+	return cr.GetStringSlice(config.Path(PathCurrencyAllow), config.ScopeStore(r))
+}
+
+// InstalledCurrencies returns all installed currency codes from the default scope.
+func InstalledCurrencies(cr config.Reader) []string {
+	return cr.GetStringSlice(config.Path(PathSystemCurrencyInstalled))
+}
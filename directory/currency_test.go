@@ -0,0 +1,42 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/directory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedCurrencies(t *testing.T) {
+	m := config.NewManager()
+	m.ApplyDefaults(directory.PackageConfiguration)
+	assert.Exactly(t, []string{"USD", "EUR"}, directory.AllowedCurrencies(m, nil))
+
+	assert.NoError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value("CHF,EUR,USD"), config.ScopeStore(config.ScopeID(2))))
+	assert.Exactly(t, []string{"CHF", "EUR", "USD"}, directory.AllowedCurrencies(m, config.ScopeID(2)))
+	assert.Contains(t, directory.InstalledCurrencies(m), "CHF")
+}
+
+func TestAllowedCurrenciesWithoutSections(t *testing.T) {
+	m := config.NewManager()
+	assert.NoError(t, m.Write(config.Path(directory.PathSystemCurrencyInstalled), config.Value("USD,EUR,CHF")))
+	assert.NoError(t, m.Write(config.Path(directory.PathCurrencyAllow), config.Value("EUR,CHF"), config.ScopeStore(config.ScopeID(2))))
+
+	assert.Exactly(t, []string{"USD", "EUR", "CHF"}, directory.InstalledCurrencies(m))
+	assert.Exactly(t, []string{"EUR", "CHF"}, directory.AllowedCurrencies(m, config.ScopeID(2)))
+}
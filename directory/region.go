@@ -27,3 +27,8 @@ type (
 func ShowNonRequiredState(cr config.Reader, r config.ScopeIDer) bool {
 	return cr.GetBool(config.ScopeStore(r), config.Path(PathDisplayAllStates))
 }
+
+// StatesRequired returns the ISO2 country codes for which a state is required.
+func StatesRequired(cr config.Reader) []string {
+	return cr.GetStringSlice(config.Path(PathStatesRequired))
+}
@@ -15,53 +15,174 @@
 package directory
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/i18n"
+	"github.com/corestoreio/csfw/utils"
 	"github.com/corestoreio/csfw/utils/log"
-	"github.com/juju/errgo"
+	"golang.org/x/text/display"
+	"golang.org/x/text/language"
 )
 
 type (
 	// SourceCurrencyAll used in Path: `system/currency/installed`,
-	SourceCurrencyAll struct {
-		mc config.ModelConstructor
-	}
+	SourceCurrencyAll struct{}
+
+	// SourceCurrency lists the installed currencies. Used in Path:
+	// `currency/options/base`, `currency/options/default` and `currency/options/allow`.
+	// Magento\Config\Model\Config\Source\Locale\Currency
+	SourceCurrency struct{}
+
+	// SourceCountry lists all countries with their names in the locale of the
+	// store. Magento\Directory\Model\Config\Source\Country
+	SourceCountry struct{}
+
+	// SourceLocale lists all supported locales of the i18n package. Used in
+	// Path: `general/locale/code`. Magento\Config\Model\Config\Source\Locale
+	SourceLocale struct{}
+
+	// SourceTimezone lists all time zone identifiers. Used in Path:
+	// `general/locale/timezone`. Magento\Config\Model\Config\Source\Locale\Timezone
+	SourceTimezone struct{}
 )
 
-var _ config.FieldSourceModeller = (*SourceCurrencyAll)(nil)
+var (
+	_ config.FieldSourceModeller       = (*SourceCurrencyAll)(nil)
+	_ config.FieldSourceScopedModeller = (*SourceCurrency)(nil)
+	_ config.FieldSourceScopedModeller = (*SourceCountry)(nil)
+	_ config.FieldSourceScopedModeller = (*SourceLocale)(nil)
+	_ config.FieldSourceModeller       = (*SourceTimezone)(nil)
+)
+
+// NewSourceCurrencyAll creates a new option for all currencies.
+func NewSourceCurrencyAll() *SourceCurrencyAll {
+	return &SourceCurrencyAll{}
+}
+
+// Options returns all ISO currency codes sorted by code. The label contains
+// the code because the i18n package does not yet provide currency names.
+func (sca *SourceCurrencyAll) Options() config.ValueLabelSlice {
+	return currencyOptions(strings.Split(currenciesAll, ","))
+}
+
+// NewSourceCurrency creates a new option for the installed currencies.
+func NewSourceCurrency() *SourceCurrency {
+	return &SourceCurrency{}
+}
+
+// Options returns nil because the installed currencies require a ConfigReader.
+func (sc *SourceCurrency) Options() config.ValueLabelSlice {
+	return sc.ScopedOptions(config.ModelConstructor{})
+}
 
-// NewSourceCurrencyAll creates a new option for all currencies. If one argument of
-// the ModelConstructor has been provided you may skip the calling of Construct().
-func NewSourceCurrencyAll(mc ...config.ModelConstructor) *SourceCurrencyAll {
-	sca := &SourceCurrencyAll{}
-	if len(mc) == 1 {
-		if err := sca.Construct(mc[0]); err != nil {
-			log.Error("SourceCurrencyAll=NewSourceCurrencyAll", "err", err)
+// ScopedOptions returns the installed currencies sorted by code. Returns nil
+// without a ConfigReader.
+func (sc *SourceCurrency) ScopedOptions(mc config.ModelConstructor) config.ValueLabelSlice {
+	if mc.ConfigReader == nil {
+		return nil
+	}
+	return currencyOptions(InstalledCurrencies(mc.ConfigReader))
+}
+
+// NewSourceCountry creates a new option for all countries.
+func NewSourceCountry() *SourceCountry {
+	return &SourceCountry{}
+}
+
+// Options returns the countries with English labels.
+func (sc *SourceCountry) Options() config.ValueLabelSlice {
+	return sc.ScopedOptions(config.ModelConstructor{})
+}
+
+// ScopedOptions returns the ISO 3166 country codes sorted by the name of the
+// country in the locale of the store. Without a ConfigReader the labels are English.
+func (sc *SourceCountry) ScopedOptions(mc config.ModelConstructor) config.ValueLabelSlice {
+	n := display.Regions(storeLocale(mc))
+	vls := make(config.ValueLabelSlice, len(i18n.Countries))
+	for i, r := range i18n.Countries {
+		vls[i] = config.ValueLabel{Value: r.String(), Label: name(n, r, r.String())}
+	}
+	return vls.SortByLabel(utils.SortAsc)
+}
+
+// NewSourceLocale creates a new option for all supported locales.
+func NewSourceLocale() *SourceLocale {
+	return &SourceLocale{}
+}
+
+// Options returns the supported locales whose second name is English.
+func (sl *SourceLocale) Options() config.ValueLabelSlice {
+	return sl.ScopedOptions(config.ModelConstructor{})
+}
+
+// ScopedOptions returns the supported locales sorted by label. The label
+// contains the name of the locale in its own language and in the language of
+// the store, e.g. Deutsch (Deutschland) / German (Germany). Without a
+// ConfigReader the second name is English.
+func (sl *SourceLocale) ScopedOptions(mc config.ModelConstructor) config.ValueLabelSlice {
+	n := display.Tags(storeLocale(mc))
+	vls := make(config.ValueLabelSlice, len(i18n.LocaleSupported))
+	for i, l := range i18n.LocaleSupported {
+		vls[i] = config.ValueLabel{Value: l, Label: l}
+		t, err := i18n.GetLocaleTag(l)
+		if err != nil {
+			continue
 		}
+		vls[i].Label = name(display.Self, t, l) + " / " + name(n, t, l)
 	}
-	return sca
+	return vls.SortByLabel(utils.SortAsc)
 }
 
-// Construct sets the necessary options
-func (sca *SourceCurrencyAll) Construct(mc config.ModelConstructor) error {
-	if mc.ConfigReader == nil {
-		return errgo.New("ConfigReader is required")
+// NewSourceTimezone creates a new option for all time zones.
+func NewSourceTimezone() *SourceTimezone {
+	return &SourceTimezone{}
+}
+
+// Options returns the time zone identifiers sorted by identifier.
+func (st *SourceTimezone) Options() config.ValueLabelSlice {
+	vls := make(config.ValueLabelSlice, len(i18n.Timezones))
+	for i, tz := range i18n.Timezones {
+		vls[i] = config.ValueLabel{Value: tz, Label: tz}
 	}
-	if mc.Scope == nil {
-		return errgo.New("Scope is required")
+	return vls
+}
+
+// currencyOptions creates the options for currency codes sorted by code.
+func currencyOptions(codes []string) config.ValueLabelSlice {
+	vls := make(config.ValueLabelSlice, 0, len(codes))
+	for _, c := range codes {
+		if c = strings.TrimSpace(c); c != "" {
+			vls = append(vls, config.ValueLabel{Value: c, Label: c})
+		}
 	}
-	sca.mc = mc
-	return nil
+	return vls.SortByValue(utils.SortAsc)
 }
-func (sca *SourceCurrencyAll) Options() config.ValueLabelSlice {
-	// Magento\Framework\Locale\Resolver
-	// grep locale from general/locale/code scope::store for the current store ID
-	// the store locale greps the currencies from http://php.net/manual/en/class.resourcebundle.php
-	// in the correct language
-	storeLocale := sca.mc.ConfigReader.GetString(config.Path(PathDefaultLocale), config.ScopeStore(sca.mc.Scope))
 
-	fmt.Printf("\nstoreLocale: %s\n", storeLocale)
+// storeLocale returns the locale of the store scope of the ModelConstructor.
+// Falls back to i18n.LocaleDefault.
+func storeLocale(mc config.ModelConstructor) language.Tag {
+	l := i18n.LocaleDefault
+	if mc.ConfigReader != nil {
+		if sl := mc.ConfigReader.GetString(config.Path(PathDefaultLocale), config.ScopeStore(mc.Scope)); sl != "" {
+			l = sl
+		}
+	}
+	t, err := i18n.GetLocaleTag(l)
+	if err != nil {
+		log.Error("directory.storeLocale.GetLocaleTag", "err", err, "locale", l)
+		return language.English
+	}
+	return t
+}
 
-	return nil
+// name returns the name of x or the fallback if the Namer is nil or has no name.
+func name(n display.Namer, x interface{}, fallback string) string {
+	if n == nil {
+		return fallback
+	}
+	if s := n.Name(x); s != "" {
+		return s
+	}
+	return fallback
 }
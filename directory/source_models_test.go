@@ -19,7 +19,9 @@ import (
 
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/directory"
+	"github.com/corestoreio/csfw/i18n"
 	"github.com/corestoreio/csfw/utils/log"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -28,25 +30,54 @@ func init() {
 }
 
 func TestSourceCurrencyAll(t *testing.T) {
+	opts := directory.NewSourceCurrencyAll().Options()
+	assert.True(t, len(opts) > 150)
+	assert.Exactly(t, config.ValueLabel{Value: "AED", Label: "AED"}, opts[0])
+}
+
+func TestSourceCurrency(t *testing.T) {
+	m := config.NewManager()
+	sc := directory.NewSourceCurrency()
+	assert.Nil(t, sc.Options())
+
+	m.ApplyDefaults(directory.PackageConfiguration)
+	assert.NoError(t, m.Write(config.Path(directory.PathSystemCurrencyInstalled), config.Value("USD,CHF,EUR")))
+	assert.Exactly(t, `[{"Value":"CHF","Label":"CHF"},{"Value":"EUR","Label":"EUR"},{"Value":"USD","Label":"USD"}]`+"\n", sc.ScopedOptions(config.ModelConstructor{ConfigReader: m}).ToJSON())
+
+	// the models of the package configuration work without further setup
+	f, err := directory.PackageConfiguration.FindFieldByPath(directory.PathCurrencyAllow)
+	assert.NoError(t, err)
+	assert.Len(t, f.SourceModel.(config.FieldSourceScopedModeller).ScopedOptions(config.ModelConstructor{ConfigReader: m}), 3)
+}
 
-	r := config.NewMockReader(
-		config.MockString(func(path string) string {
-			t.Log(path)
-			switch path {
-			case config.MockPathScopeStore(1, directory.PathDefaultLocale):
-				return "de_CH"
+func TestSourceCountryLocaleTimezone(t *testing.T) {
+	contains := func(vls config.ValueLabelSlice, value string) bool {
+		for _, vl := range vls {
+			if vl.Value == value {
+				return true
 			}
-			return "Not Found"
-		}),
-	)
+		}
+		return false
+	}
 
-	s := config.ScopeID(1)
+	countries := directory.NewSourceCountry().Options()
+	assert.Len(t, countries, len(i18n.Countries))
+	assert.True(t, contains(countries, "DE"))
 
-	sca := directory.NewSourceCurrencyAll(config.ModelConstructor{
-		ConfigReader: r,
-		Scope:        s,
-	})
+	locales := directory.NewSourceLocale().ScopedOptions(config.ModelConstructor{ConfigReader: config.NewMockReader()})
+	assert.Len(t, locales, len(i18n.LocaleSupported))
+	assert.True(t, contains(locales, "en_US"))
 
-	t.Logf("\n%#v\n", sca.Options())
+	timezones := directory.NewSourceTimezone().Options()
+	assert.Len(t, timezones, len(i18n.Timezones))
+	assert.True(t, contains(timezones, "Europe/Berlin"))
+	assert.True(t, contains(timezones, "UTC"))
 
+	var ss config.SectionSlice
+	assert.NoError(t, ss.MergeMultiple(directory.PackageConfiguration))
+	for _, p := range []string{"general/locale/code", "general/locale/timezone"} {
+		f, err := ss.FindFieldByPath(p)
+		assert.NoError(t, err)
+		assert.NotNil(t, f.SourceModel, p)
+	}
 }
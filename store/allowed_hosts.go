@@ -0,0 +1,58 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"strings"
+	"sync"
+)
+
+// AllowedHosts contains the hosts which may replace the placeholder
+// {{base_url}} in Store.RequestBaseURL(). The Host header of a request is
+// chosen by the client, so only listed hosts will be used for the URLs.
+type AllowedHosts struct {
+	mu    sync.RWMutex
+	hosts map[string]bool
+}
+
+// DefaultAllowedHosts will be used by Store.RequestBaseURL(). It allows no
+// host, so {{base_url}} falls back to config.PathCSBaseURL. Set the hosts at
+// the start of the app via DefaultAllowedHosts.Set().
+var DefaultAllowedHosts = NewAllowedHosts()
+
+// NewAllowedHosts creates a new empty list which allows no host.
+func NewAllowedHosts() *AllowedHosts {
+	return &AllowedHosts{}
+}
+
+// Set replaces the allowed hosts. A host contains the port if the URLs
+// contain it, e.g. shop.io or shop.io:8080.
+func (ah *AllowedHosts) Set(hosts ...string) {
+	m := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		m[strings.ToLower(h)] = true
+	}
+	ah.mu.Lock()
+	ah.hosts = m
+	ah.mu.Unlock()
+}
+
+// Contains returns true if the host of a request has been allowed. The
+// comparison ignores the case.
+func (ah *AllowedHosts) Contains(host string) bool {
+	ah.mu.RLock()
+	defer ah.mu.RUnlock()
+	return ah.hosts[strings.ToLower(host)]
+}
@@ -0,0 +1,47 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedHosts(t *testing.T) {
+	ah := store.NewAllowedHosts()
+	assert.False(t, ah.Contains("shop.io"))
+
+	ah.Set("shop.io", "Shop.CH:8080")
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"shop.io", true},
+		{"SHOP.io", true},
+		{"shop.ch:8080", true},
+		{"shop.ch", false},
+		{"shop.io:8080", false},
+		{"evil.io", false},
+		{"", false},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.want, ah.Contains(test.host), "Index %d", i)
+	}
+
+	ah.Set()
+	assert.False(t, ah.Contains("shop.io"))
+}
@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:generate go run ../codegen/configAccessors/main.go -package store -o config_accessors.go
+
 package store
 
 import (
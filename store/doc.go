@@ -19,6 +19,60 @@ Usage
 
 	m := store.NewManager(options ...)
 
+Persisting
+
+If the Storager implements StorageMutator, like Storage, the Manager creates,
+updates and deletes websites, groups and stores in the database and clears its
+caches afterwards. The codes, the relations and the defaults will be validated
+before. Mutate() runs the changes in a transaction and reloads the data after
+a rollback:
+
+	err := m.Mutate(dbrSess, func(tx *dbr.Tx) error {
+		return m.CreateStore(tx, &store.TableStore{Code: ..., WebsiteID: 1, GroupID: 1, Name: "France", IsActive: true})
+	})
+
+The admin website, group and store with ID 0 cannot be deleted.
+
+Middleware
+
+The StoreMiddleware resolves the Store of a request once from a JSON web token,
+the store cookie or the ___store parameter and falls back to the appStore. A
+Store from the token, a RequestResolver or the ___store parameter sets the
+store cookie, or deletes it for the default store of the website. The handlers
+read the Store from the context of the request:
+
+	mw := store.NewStoreMiddleware(m, config.ScopeWebsiteID, store.SetMiddlewareToken(tokenFunc))
+	http.Handle("/", mw.Handle(myHandler))
+
+	func myHandler(w http.ResponseWriter, r *http.Request) {
+		s, ok := store.FromContext(r.Context())
+		...
+	}
+
+If websites run on different domains or the store codes are part of the URL
+(web/url/use_store) add the RequestResolvers. Their results will be checked by
+GetRequestStore() like the cookie:
+
+	mw := store.NewStoreMiddleware(m, config.ScopeDefaultID,
+		store.SetMiddlewareResolvers(store.ResolveByPathPrefix, store.ResolveByHost))
+
+The hosts of the base URLs will be collected on the first request. Writing a
+base URL to the config.Manager collects them again on the next request. If the
+base URLs change in another way call ClearCache().
+
+Base URLs
+
+Store.RequestBaseURL() returns the secure web, static or media URL for HTTPS
+requests if web/secure/use_in_frontend is enabled and replaces {{base_url}}
+with the scheme and host of the request. Only hosts of DefaultAllowedHosts will
+be used, any other host will be replaced with web/corestore/base_url. Behind a
+reverse proxy which terminates TLS its network must be trusted to read the
+Forwarded or X-Forwarded-Proto header. The headers will be read from right to
+left past all trusted proxies:
+
+	store.DefaultAllowedHosts.Set("shop.io", "shop.ch")
+	err := store.DefaultTrustedProxies.Set("10.0.0.0/8", "192.168.1.1")
+	url := s.RequestBaseURL(r, config.URLTypeStatic)
 
 */
 package store
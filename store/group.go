@@ -59,6 +59,7 @@ var (
 	ErrGroupWebsiteNotFound = errors.New("Group Website not found or nil or ID do not match")
 )
 var _ config.ScopeIDer = (*Group)(nil)
+var _ config.WebsiteIDer = (*Group)(nil)
 
 // SetGroupConfig sets the configuration Reader to the Group.
 // Default reader is config.DefaultManager
@@ -104,6 +105,12 @@ func (g *Group) ScopeID() int64 {
 	return g.g.GroupID
 }
 
+// WebsiteID returns the ID of the website. Satisfies the interface config.WebsiteIDer
+// so that config lookups of the group fall back to the website scope.
+func (g *Group) WebsiteID() int64 {
+	return g.g.WebsiteID
+}
+
 // ApplyOptions sets the options to a Group.
 func (g *Group) ApplyOptions(opts ...GroupOption) *Group {
 	for _, opt := range opts {
@@ -32,7 +32,7 @@ type (
 		cr config.Reader
 
 		// storage get set of websites, groups and stores and also type assertion to StorageMutator for
-		// ReInit and Persisting
+		// persisting, see CreateWebsite() etc.
 		storage Storager
 		mu      sync.RWMutex
 
@@ -55,6 +55,14 @@ type (
 		// defaultStore some one must be always default.
 		defaultStore *Store
 
+		// hosts maps the lower case hosts of the base URLs to their stores,
+		// see ResolveByHost(). Built on the first request and after
+		// ClearCache() or a changed base URL again on the next request.
+		hosts map[string]StoreSlice
+		// hostsSubscribed is true once the Manager listens to the changes of
+		// the base URLs in the config.Manager.
+		hostsSubscribed bool
+
 		// HealthJob allows profiling and error handling. Default is a noop type
 		// and can be overridden after creating a new Manager. @todo
 		// HealthJob health.EventReceiver
@@ -72,6 +80,8 @@ var (
 	ErrHashRetrieverNil      = errors.New("Hash argument is nil")
 )
 
+var _ config.ScopeHierarchy = (*Manager)(nil)
+
 // NewManager creates a new store manager which handles websites, store groups and stores.
 // @todo Default Storager should be a hardcoded Table* struct ...
 func NewManager(opts ...ManagerOption) *Manager {
@@ -161,20 +171,29 @@ func (sm *Manager) InitByRequest(res http.ResponseWriter, req *http.Request, sco
 		}
 		// also delete and re-set a new cookie
 		if reqStore != nil && reqStore.Data().Code.String == reqStoreCode {
-			wds, err := reqStore.Website().DefaultStore()
-			if err != nil {
+			if err := updateCookie(res, reqStore); err != nil {
 				return nil, errgo.Mask(err)
 			}
-			if wds.Data().Code.String == reqStoreCode {
-				reqStore.DeleteCookie(res) // cookie not needed anymore
-			} else {
-				reqStore.SetCookie(res) // make sure we force set the new store
-			}
 		}
 	}
 	return reqStore, nil // can be nil,nil
 }
 
+// updateCookie deletes the cookie if s is the default store of its website
+// because the cookie is not needed anymore, otherwise it sets the cookie of s.
+func updateCookie(res http.ResponseWriter, s *Store) error {
+	wds, err := s.Website().DefaultStore()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	if wds.Data().Code.String == s.Data().Code.String {
+		s.DeleteCookie(res)
+	} else {
+		s.SetCookie(res)
+	}
+	return nil
+}
+
 // InitByToken returns a Store pointer from a JSON web token. If the store code is invalid,
 // this function can return nil,nil
 func (sm *Manager) InitByToken(t *jwt.Token, scopeType config.ScopeGroup) (*Store, error) {
@@ -355,6 +374,29 @@ func (sm *Manager) Store(r ...config.ScopeIDer) (*Store, error) {
 	return sm.storeMap[key], errgo.Mask(err)
 }
 
+// ParentWebsiteID returns the website ID of a store or group. Satisfies the
+// interface config.ScopeHierarchy and can be set to a config.Manager to enable
+// the fallback store -> website -> default:
+//
+//	config.DefaultManager.SetScopeHierarchy(storeManager)
+func (sm *Manager) ParentWebsiteID(sg config.ScopeGroup, id int64) (int64, error) {
+	switch sg {
+	case config.ScopeStoreID:
+		s, err := sm.Store(config.ScopeID(id))
+		if err != nil {
+			return 0, errgo.Mask(err)
+		}
+		return s.Data().WebsiteID, nil
+	case config.ScopeGroupID:
+		g, err := sm.Group(config.ScopeID(id))
+		if err != nil {
+			return 0, errgo.Mask(err)
+		}
+		return g.Data().WebsiteID, nil
+	}
+	return 0, ErrUnsupportedScopeGroup
+}
+
 // Stores returns a cached Store slice. Can return an error when the website or
 // the group cannot be found.
 func (sm *Manager) Stores() (StoreSlice, error) {
@@ -395,11 +437,113 @@ func (sm *Manager) activeStore(r config.ScopeIDer) (*Store, error) {
 func (sm *Manager) ReInit(dbrSess dbr.SessionRunner, cbs ...csdb.DbrSelectCb) error {
 	err := sm.storage.ReInit(dbrSess, cbs...)
 	if err == nil {
-		sm.ClearCache()
+		sm.clearCaches()
+	}
+	return err
+}
+
+// Mutate runs f within a new transaction and commits it if f returns nil. Use
+// the transaction for the mutations, e.g. CreateStore(). If f or the commit
+// fails, the transaction will be rolled back and the storage reloaded with
+// dbrSess because the in-memory data already contains the mutations. Returns
+// the error of f or of the commit.
+//
+//	err := m.Mutate(dbrSess, func(tx *dbr.Tx) error {
+//		if err := m.CreateGroup(tx, tg); err != nil {
+//			return err
+//		}
+//		return m.CreateStore(tx, ts)
+//	})
+func (sm *Manager) Mutate(dbrSess *dbr.Session, f func(*dbr.Tx) error) error {
+	tx, err := dbrSess.Begin()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	if err = f(tx); err != nil {
+		tx.Rollback()
+	} else if err = tx.Commit(); err == nil {
+		return nil
+	}
+	// the in-memory data contains the rolled back mutations
+	if rerr := sm.ReInit(dbrSess); rerr != nil {
+		return errgo.Mask(rerr)
 	}
 	return err
 }
 
+// mutate runs f with the StorageMutator of the storage and clears the caches
+// after a successful mutation. Returns ErrStorageNotMutable if the storage
+// does not implement StorageMutator.
+func (sm *Manager) mutate(f func(StorageMutator) error) error {
+	mut, ok := sm.storage.(StorageMutator)
+	if !ok {
+		return ErrStorageNotMutable
+	}
+	if err := f(mut); err != nil {
+		return err
+	}
+	sm.clearCaches()
+	return nil
+}
+
+// clearCaches clears the internal caches, the cached website IDs and the
+// Snapshots of the config.Manager because a store might have changed its
+// website.
+func (sm *Manager) clearCaches() {
+	sm.ClearCache()
+	if si, ok := sm.cr.(interface {
+		InvalidateScopeHierarchy()
+	}); ok {
+		si.InvalidateScopeHierarchy()
+	}
+}
+
+// CreateWebsite persists a new website and clears the cache. See StorageMutator.
+func (sm *Manager) CreateWebsite(dbrSess dbr.SessionRunner, tw *TableWebsite) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.CreateWebsite(dbrSess, tw) })
+}
+
+// UpdateWebsite persists a changed website and clears the cache. See StorageMutator.
+func (sm *Manager) UpdateWebsite(dbrSess dbr.SessionRunner, tw *TableWebsite) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.UpdateWebsite(dbrSess, tw) })
+}
+
+// DeleteWebsite deletes a website with its groups and stores and clears the cache.
+// See StorageMutator.
+func (sm *Manager) DeleteWebsite(dbrSess dbr.SessionRunner, websiteID int64) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.DeleteWebsite(dbrSess, websiteID) })
+}
+
+// CreateGroup persists a new group and clears the cache. See StorageMutator.
+func (sm *Manager) CreateGroup(dbrSess dbr.SessionRunner, tg *TableGroup) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.CreateGroup(dbrSess, tg) })
+}
+
+// UpdateGroup persists a changed group and clears the cache. See StorageMutator.
+func (sm *Manager) UpdateGroup(dbrSess dbr.SessionRunner, tg *TableGroup) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.UpdateGroup(dbrSess, tg) })
+}
+
+// DeleteGroup deletes a group with its stores and clears the cache. See StorageMutator.
+func (sm *Manager) DeleteGroup(dbrSess dbr.SessionRunner, groupID int64) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.DeleteGroup(dbrSess, groupID) })
+}
+
+// CreateStore persists a new store and clears the cache. See StorageMutator.
+func (sm *Manager) CreateStore(dbrSess dbr.SessionRunner, ts *TableStore) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.CreateStore(dbrSess, ts) })
+}
+
+// UpdateStore persists a changed store and clears the cache. See StorageMutator.
+func (sm *Manager) UpdateStore(dbrSess dbr.SessionRunner, ts *TableStore) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.UpdateStore(dbrSess, ts) })
+}
+
+// DeleteStore deletes a store and clears the cache. See StorageMutator.
+func (sm *Manager) DeleteStore(dbrSess dbr.SessionRunner, storeID int64) error {
+	return sm.mutate(func(mut StorageMutator) error { return mut.DeleteStore(dbrSess, storeID) })
+}
+
 // ClearCache resets the internal caches which stores the pointers to a Website, Group or Store and
 // all related slices. Please use with caution. ReInit() also uses this method.
 // Providing argument true clears also the internal appStore cache.
@@ -425,6 +569,7 @@ func (sm *Manager) ClearCache(clearAll ...bool) {
 	sm.groups = nil
 	sm.stores = nil
 	sm.defaultStore = nil
+	sm.hosts = nil
 	// do not clear currentStore as this one depends on the init funcs
 	if 1 == len(clearAll) && clearAll[0] {
 		sm.appStore = nil
@@ -434,7 +579,7 @@ func (sm *Manager) ClearCache(clearAll ...bool) {
 // IsCacheEmpty returns true if the internal cache is empty.
 func (sm *Manager) IsCacheEmpty() bool {
 	return len(sm.websiteMap) == 0 && len(sm.groupMap) == 0 && len(sm.storeMap) == 0 &&
-		sm.websites == nil && sm.groups == nil && sm.stores == nil && sm.defaultStore == nil
+		sm.websites == nil && sm.groups == nil && sm.stores == nil && sm.defaultStore == nil && sm.hosts == nil
 }
 
 // notRetriever checks if variadic ScopeIDer is nil or has more than two entries
@@ -298,6 +298,35 @@ func TestNewManagerGroupInit(t *testing.T) {
 	assert.Equal(t, 2, g.Data().DefaultStoreID)
 }
 
+func TestNewManagerParentWebsiteID(t *testing.T) {
+	sm := getTestManager(func(ms *mockStorage) {
+		ms.g = func() (*store.Group, error) {
+			return store.NewGroup(&store.TableGroup{GroupID: 3, WebsiteID: 2, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4}), nil
+		}
+		ms.s = func() (*store.Store, error) {
+			return store.NewStore(
+				&store.TableStore{StoreID: 4, Code: dbr.NullString{NullString: sql.NullString{String: "uk", Valid: true}}, WebsiteID: 2, GroupID: 3, Name: "United Kingdom", SortOrder: 10, IsActive: true},
+				&store.TableWebsite{WebsiteID: 2, Code: dbr.NullString{NullString: sql.NullString{String: "oz", Valid: true}}, Name: dbr.NullString{NullString: sql.NullString{String: "OZ", Valid: true}}, SortOrder: 20, DefaultGroupID: 3, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: false, Valid: true}}},
+				&store.TableGroup{GroupID: 3, WebsiteID: 2, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4},
+			), nil
+		}
+	})
+
+	id, err := sm.ParentWebsiteID(config.ScopeStoreID, 4)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), id)
+
+	id, err = sm.ParentWebsiteID(config.ScopeGroupID, 3)
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(2), id)
+
+	_, err = sm.ParentWebsiteID(config.ScopeWebsiteID, 2)
+	assert.EqualError(t, err, store.ErrUnsupportedScopeGroup.Error())
+
+	_, err = getTestManager().ParentWebsiteID(config.ScopeStoreID, 5)
+	assert.EqualError(t, err, store.ErrStoreNotFound.Error())
+}
+
 func TestNewManagerGroups(t *testing.T) {
 	managerGroups := getTestManager(func(ms *mockStorage) {
 		ms.gs = func() (store.GroupSlice, error) {
@@ -0,0 +1,170 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/dgrijalva/jwt-go"
+)
+
+type (
+	// TokenFunc extracts a JSON web token from a request. It returns nil,nil
+	// if the request does not contain a token.
+	TokenFunc func(*http.Request) (*jwt.Token, error)
+
+	// ErrorHandlerFunc handles errors of the StoreMiddleware, e.g. when the
+	// Manager has not been initialized.
+	ErrorHandlerFunc func(http.ResponseWriter, *http.Request, error)
+
+	// StoreMiddleware resolves the requested Store once per request and puts it
+	// into the context of the request. Use FromContext() to read it.
+	StoreMiddleware struct {
+		sm         *Manager
+		scopeType  config.ScopeGroup
+		token      TokenFunc
+		resolvers  []RequestResolver
+		errHandler ErrorHandlerFunc
+	}
+
+	// StoreMiddlewareOption can be used as an argument in NewStoreMiddleware to configure it.
+	StoreMiddlewareOption func(*StoreMiddleware)
+
+	// ctxKey is the unexported type for the context key to avoid collisions.
+	ctxKey struct{}
+)
+
+// SetMiddlewareToken sets the function to extract a JSON web token from the
+// request. A store code in the token claim has precedence over the cookie and
+// the ___store parameter.
+func SetMiddlewareToken(f TokenFunc) StoreMiddlewareOption {
+	return func(mw *StoreMiddleware) { mw.token = f }
+}
+
+// SetMiddlewareResolvers sets the resolvers which find the store in the URL,
+// e.g. ResolveByPathPrefix and ResolveByHost. They have precedence over the
+// cookie and the ___store parameter, see InitByResolvers().
+func SetMiddlewareResolvers(rs ...RequestResolver) StoreMiddlewareOption {
+	return func(mw *StoreMiddleware) { mw.resolvers = rs }
+}
+
+// SetMiddlewareErrorHandler sets a custom error handler. The default handler
+// responds with a 500 Internal Server Error.
+func SetMiddlewareErrorHandler(f ErrorHandlerFunc) StoreMiddlewareOption {
+	return func(mw *StoreMiddleware) { mw.errHandler = f }
+}
+
+// NewStoreMiddleware creates a new middleware. The Manager must be initialized
+// with Init() before handling the first request. The scopeType is the same as
+// in InitByRequest().
+func NewStoreMiddleware(sm *Manager, scopeType config.ScopeGroup, opts ...StoreMiddlewareOption) *StoreMiddleware {
+	mw := &StoreMiddleware{
+		sm:         sm,
+		scopeType:  scopeType,
+		errHandler: defaultErrorHandler,
+	}
+	for _, o := range opts {
+		if o != nil {
+			o(mw)
+		}
+	}
+	return mw
+}
+
+// Handle wraps h. The Store will be resolved in the following order:
+// 1. JSON web token, if SetMiddlewareToken() has been used
+// 2. the URL, if SetMiddlewareResolvers() has been used
+// 3. cookie and ___store parameter, see InitByRequest()
+// 4. the appStore from Init()
+// Errors of 1. to 3. will be ignored because of the fallback to the appStore.
+// For a Store of 1. or 2. the cookie will be set or deleted like in
+// InitByRequest() with the ___store parameter, so the next request without a
+// token or a matching URL stays in the same Store.
+func (mw *StoreMiddleware) Handle(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := mw.resolve(w, r)
+		if err != nil {
+			mw.errHandler(w, r, err)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(NewContext(r.Context(), s)))
+	})
+}
+
+// resolve returns the Store for the request or an error if the appStore is not set.
+func (mw *StoreMiddleware) resolve(w http.ResponseWriter, r *http.Request) (*Store, error) {
+	if mw.token != nil {
+		t, err := mw.token(r)
+		if err == nil && t != nil {
+			var s *Store
+			if s, err = mw.sm.InitByToken(t, mw.scopeType); s != nil && err == nil {
+				mw.updateCookie(w, r, s)
+				return s, nil
+			}
+		}
+		if err != nil && log.IsDebug() {
+			log.Debug("store.StoreMiddleware.resolve.token", "err", err, "url", r.URL.String())
+		}
+	}
+
+	if len(mw.resolvers) > 0 {
+		s, err := mw.sm.InitByResolvers(r, mw.scopeType, mw.resolvers...)
+		if s != nil && err == nil {
+			mw.updateCookie(w, r, s)
+			return s, nil
+		}
+		if err != nil && log.IsDebug() {
+			log.Debug("store.StoreMiddleware.resolve.resolvers", "err", err, "url", r.URL.String())
+		}
+	}
+
+	s, err := mw.sm.InitByRequest(w, r, mw.scopeType)
+	if s != nil && err == nil {
+		return s, nil
+	}
+	if err != nil && log.IsDebug() {
+		log.Debug("store.StoreMiddleware.resolve.request", "err", err, "url", r.URL.String())
+	}
+	return mw.sm.Store()
+}
+
+// updateCookie sets or deletes the cookie of a Store which has not been
+// resolved by InitByRequest(). An error will only be logged because the Store
+// is valid.
+func (mw *StoreMiddleware) updateCookie(w http.ResponseWriter, r *http.Request, s *Store) {
+	if err := updateCookie(w, s); err != nil {
+		log.Error("store.StoreMiddleware.updateCookie", "err", err, "url", r.URL.String())
+	}
+}
+
+// defaultErrorHandler writes the status text of a 500 error.
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, _ error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// NewContext returns a copy of ctx which contains the Store.
+func NewContext(ctx context.Context, s *Store) context.Context {
+	return context.WithValue(ctx, ctxKey{}, s)
+}
+
+// FromContext returns the Store from ctx. ok is false if the context does not
+// contain a Store.
+func FromContext(ctx context.Context) (s *Store, ok bool) {
+	s, ok = ctx.Value(ctxKey{}).(*Store)
+	return s, ok && s != nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/store"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// middlewareStoreCode writes the code of the Store in the context.
+var middlewareStoreCode = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	s, ok := store.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Store not found", http.StatusTeapot)
+		return
+	}
+	w.Write([]byte(s.Data().Code.String))
+})
+
+func TestStoreMiddleware(t *testing.T) {
+	getToken := func(code string) func(*http.Request) (*jwt.Token, error) {
+		return func(_ *http.Request) (*jwt.Token, error) {
+			if code == "" {
+				return nil, errors.New("Token not found")
+			}
+			t := jwt.New(jwt.SigningMethodHS256)
+			t.Claims[store.CookieName] = code
+			return t, nil
+		}
+	}
+
+	tests := []struct {
+		req        *http.Request
+		scopeType  config.ScopeGroup
+		token      string
+		wantCode   string
+		wantCookie string
+	}{
+		{getTestRequest(t, "GET", "http://cs.io", nil), config.ScopeStoreID, "", "de", ""},
+		{getTestRequest(t, "GET", "http://cs.io", &http.Cookie{Name: store.CookieName, Value: "uk"}), config.ScopeStoreID, "", "uk", ""},
+		{getTestRequest(t, "GET", "http://cs.io/?"+store.HTTPRequestParamStore+"=ch", nil), config.ScopeWebsiteID, "", "ch", store.CookieName + "=ch;"},
+		{getTestRequest(t, "GET", "http://cs.io/?"+store.HTTPRequestParamStore+"=at", nil), config.ScopeWebsiteID, "", "at", store.CookieName + "=;"},
+		// not allowed in the website scope, falls back to the appStore
+		{getTestRequest(t, "GET", "http://cs.io/?"+store.HTTPRequestParamStore+"=au", nil), config.ScopeWebsiteID, "", "de", ""},
+		{getTestRequest(t, "GET", "http://cs.io/?"+store.HTTPRequestParamStore+"=cz", nil), config.ScopeWebsiteID, "", "de", ""},
+		// the token has precedence
+		{getTestRequest(t, "GET", "http://cs.io/?"+store.HTTPRequestParamStore+"=ch", nil), config.ScopeStoreID, "nz", "nz", store.CookieName + "=nz;"},
+		{getTestRequest(t, "GET", "http://cs.io", &http.Cookie{Name: store.CookieName, Value: "uk"}), config.ScopeStoreID, "at", "at", store.CookieName + "=;"},
+		{getTestRequest(t, "GET", "http://cs.io", &http.Cookie{Name: store.CookieName, Value: "uk"}), config.ScopeWebsiteID, "nz", "uk", ""},
+	}
+
+	for i, test := range tests {
+		sm := store.NewManager(store.SetManagerStorage(newMutatorStorage()))
+		if err := sm.Init(config.ScopeCode("de"), config.ScopeStoreID); err != nil {
+			t.Fatal(err)
+		}
+
+		var opts []store.StoreMiddlewareOption
+		if test.token != "" {
+			opts = append(opts, store.SetMiddlewareToken(getToken(test.token)))
+		}
+		rec := httptest.NewRecorder()
+		store.NewStoreMiddleware(sm, test.scopeType, opts...).Handle(middlewareStoreCode).ServeHTTP(rec, test.req)
+
+		assert.Exactly(t, http.StatusOK, rec.Code, "Index %d", i)
+		assert.Exactly(t, test.wantCode, rec.Body.String(), "Index %d", i)
+		if test.wantCookie != "" {
+			assert.Contains(t, rec.Header().Get("Set-Cookie"), test.wantCookie, "Index %d", i)
+		} else {
+			assert.Empty(t, rec.Header().Get("Set-Cookie"), "Index %d", i)
+		}
+	}
+}
+
+func TestStoreMiddlewareAppStoreNotSet(t *testing.T) {
+	sm := store.NewManager(store.SetManagerStorage(newMutatorStorage()))
+
+	rec := httptest.NewRecorder()
+	store.NewStoreMiddleware(sm, config.ScopeStoreID).Handle(middlewareStoreCode).ServeHTTP(rec, getTestRequest(t, "GET", "http://cs.io", nil))
+	assert.Exactly(t, http.StatusInternalServerError, rec.Code)
+
+	var haveErr error
+	eh := store.SetMiddlewareErrorHandler(func(w http.ResponseWriter, _ *http.Request, err error) {
+		haveErr = err
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	rec = httptest.NewRecorder()
+	store.NewStoreMiddleware(sm, config.ScopeStoreID, eh).Handle(middlewareStoreCode).ServeHTTP(rec, getTestRequest(t, "GET", "http://cs.io", nil))
+	assert.Exactly(t, http.StatusServiceUnavailable, rec.Code)
+	assert.EqualError(t, haveErr, store.ErrAppStoreNotSet.Error())
+}
+
+func TestContext(t *testing.T) {
+	r := getTestRequest(t, "GET", "http://cs.io", nil)
+	s, ok := store.FromContext(r.Context())
+	assert.Nil(t, s)
+	assert.False(t, ok)
+
+	ctx := store.NewContext(r.Context(), store.NewStore(
+		&store.TableStore{StoreID: 1, Code: nullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", IsActive: true},
+		&store.TableWebsite{WebsiteID: 1, Code: nullString("euro"), Name: nullString("Europe"), DefaultGroupID: 1},
+		&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", DefaultStoreID: 1},
+	))
+	s, ok = store.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Exactly(t, "de", s.Data().Code.String)
+}
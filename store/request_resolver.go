@@ -0,0 +1,198 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/utils/log"
+	"github.com/juju/errgo"
+)
+
+// RequestResolver returns the code or the ID of a store found in the request
+// or nil if the request does not point to a store. The result must be checked
+// with GetRequestStore(), see InitByResolvers().
+type RequestResolver func(sm *Manager, req *http.Request) (config.ScopeIDer, error)
+
+// InitByResolvers returns the Store of the first resolver whose result passes
+// GetRequestStore() and its website or group restrictions of scopeType. Returns
+// nil,nil if no resolver finds a store. If a resolver finds a store which is not
+// allowed the next resolver will be asked and the last error returned.
+func (sm *Manager) InitByResolvers(req *http.Request, scopeType config.ScopeGroup, rs ...RequestResolver) (*Store, error) {
+	if sm.appStore == nil {
+		// that means you must call Init() before executing this function.
+		return nil, ErrAppStoreNotSet
+	}
+
+	var lastErr error
+	for _, r := range rs {
+		if r == nil {
+			continue
+		}
+		id, err := r(sm, req)
+		if err != nil {
+			lastErr = errgo.Mask(err)
+			continue
+		}
+		if id == nil {
+			continue
+		}
+		s, err := sm.GetRequestStore(id, scopeType)
+		if err != nil {
+			if log.IsDebug() {
+				log.Debug("store.Manager.InitByResolvers", "err", err, "url", req.URL.String())
+			}
+			lastErr = errgo.Mask(err)
+			continue
+		}
+		return s, nil
+	}
+	return nil, lastErr
+}
+
+// ResolveByHost compares the Host header with the hosts of the secure and
+// unsecure base URLs of all stores. If more than one store matches, the store
+// from the cookie wins, then the default store of the website and then the
+// first matching store. The admin store will be ignored. The hosts will be
+// computed on the first call. A base URL written to the config.Manager of
+// SetManagerConfig() resets them, other changes require a ClearCache().
+func ResolveByHost(sm *Manager, req *http.Request) (config.ScopeIDer, error) {
+	if req == nil || req.Host == "" {
+		return nil, nil
+	}
+	matches, err := sm.storesByHost(req.Host)
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	var wdsCode string
+	if wds, err := matches[0].Website().DefaultStore(); err == nil {
+		wdsCode = wds.Data().Code.String
+	}
+	var keksCode string
+	if keks, ok := GetCodeFromCookie(req).(config.ScopeCoder); ok {
+		keksCode = keks.ScopeCode()
+	}
+	for _, code := range []string{keksCode, wdsCode} {
+		for _, s := range matches {
+			if code != "" && s.Data().Code.String == code {
+				return config.ScopeCode(code), nil
+			}
+		}
+	}
+	return config.ScopeCode(matches[0].Data().Code.String), nil
+}
+
+// hostsSubscriber resets the hosts of the Manager when a base URL changes.
+type hostsSubscriber struct {
+	sm *Manager
+}
+
+// MessageConfig implements the config.MessageReceiver interface.
+func (hs hostsSubscriber) MessageConfig(_ string, _ config.ScopeGroup, _ config.ScopeIDer) error {
+	hs.sm.mu.Lock()
+	hs.sm.hosts = nil
+	hs.sm.mu.Unlock()
+	return nil
+}
+
+// subscribeHosts subscribes once to the base URLs if the config Reader
+// publishes its changes.
+func (sm *Manager) subscribeHosts() {
+	cm, ok := sm.cr.(*config.Manager)
+	sm.mu.Lock()
+	if !ok || sm.hostsSubscribed {
+		sm.mu.Unlock()
+		return
+	}
+	sm.hostsSubscribed = true
+	sm.mu.Unlock()
+	for _, p := range []string{PathUnsecureBaseURL, PathSecureBaseURL} {
+		if _, err := cm.Subscribe(p, config.ScopeAbsentID, nil, hostsSubscriber{sm}); err != nil {
+			log.Error("store.Manager.subscribeHosts", "err", err, "path", p)
+		}
+	}
+}
+
+// storesByHost returns the stores whose secure or unsecure base URL runs on
+// the host. The map of all hosts will be built on the first call. An empty map
+// won't be cached because the configuration might not have been loaded yet.
+func (sm *Manager) storesByHost(host string) (StoreSlice, error) {
+	sm.mu.RLock()
+	hosts := sm.hosts
+	sm.mu.RUnlock()
+	if hosts != nil {
+		return hosts[strings.ToLower(host)], nil
+	}
+
+	sm.subscribeHosts()
+	stores, err := sm.Stores()
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+	hosts = make(map[string]StoreSlice)
+	for _, s := range stores {
+		if s.Data().IsDefault() {
+			continue
+		}
+		var prev string
+		for _, isSecure := range []bool{false, true} {
+			u, err := url.Parse(s.BaseURL(config.URLTypeWeb, isSecure))
+			if err != nil || u.Host == "" {
+				continue
+			}
+			if h := strings.ToLower(u.Host); h != prev {
+				hosts[h] = append(hosts[h], s)
+				prev = h
+			}
+		}
+	}
+	if len(hosts) > 0 {
+		sm.mu.Lock()
+		sm.hosts = hosts
+		sm.mu.Unlock()
+	}
+	return hosts[strings.ToLower(host)], nil
+}
+
+// ResolveByPathPrefix checks if the first path segment after the path of the
+// base URL is a store code e.g. /de/ or /shop/de/. Only stores with enabled
+// PathStoreInURL (web/url/use_store) will be considered.
+func ResolveByPathPrefix(sm *Manager, req *http.Request) (config.ScopeIDer, error) {
+	if req == nil || req.URL == nil {
+		return nil, nil
+	}
+	stores, err := sm.Stores()
+	if err != nil {
+		return nil, errgo.Mask(err)
+	}
+
+	for _, s := range stores {
+		if s.Data().IsDefault() || false == s.cr.GetBool(config.ScopeStore(s), config.Path(PathStoreInURL)) {
+			continue
+		}
+		prefix := s.Path() + s.Data().Code.String
+		if req.URL.Path == prefix || strings.HasPrefix(req.URL.Path, prefix+"/") {
+			return config.ScopeCode(s.Data().Code.String), nil
+		}
+	}
+	return nil, nil
+}
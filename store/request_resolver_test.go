@@ -0,0 +1,228 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// newResolverStorage creates the websites euro and oz with the stores de, at,
+// ch, uk and au, nz which read their base URLs from cm.
+func newResolverStorage(cm config.Reader) *store.Storage {
+	return store.NewStorage(
+		store.SetStorageConfig(cm),
+		store.SetStorageWebsites(
+			&store.TableWebsite{WebsiteID: 0, Code: nullString("admin"), Name: nullString("Admin"), SortOrder: 0, DefaultGroupID: 0, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: false, Valid: true}}},
+			&store.TableWebsite{WebsiteID: 1, Code: nullString("euro"), Name: nullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: true, Valid: true}}},
+			&store.TableWebsite{WebsiteID: 2, Code: nullString("oz"), Name: nullString("OZ"), SortOrder: 20, DefaultGroupID: 3, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: false, Valid: true}}},
+		),
+		store.SetStorageGroups(
+			&store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
+			&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 2},
+			&store.TableGroup{GroupID: 2, WebsiteID: 1, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4},
+			&store.TableGroup{GroupID: 3, WebsiteID: 2, Name: "Australia", RootCategoryID: 2, DefaultStoreID: 5},
+		),
+		store.SetStorageStores(
+			&store.TableStore{StoreID: 0, Code: nullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
+			&store.TableStore{StoreID: 1, Code: nullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 2, Code: nullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
+			&store.TableStore{StoreID: 3, Code: nullString("ch"), WebsiteID: 1, GroupID: 1, Name: "Schweiz", SortOrder: 30, IsActive: true},
+			&store.TableStore{StoreID: 4, Code: nullString("uk"), WebsiteID: 1, GroupID: 2, Name: "UK", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 5, Code: nullString("au"), WebsiteID: 2, GroupID: 3, Name: "Australia", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 6, Code: nullString("nz"), WebsiteID: 2, GroupID: 3, Name: "Kiwi", SortOrder: 30, IsActive: true},
+		),
+	)
+}
+
+// newResolverManager creates an initialized Manager whose websites run on
+// different hosts. Store uk has its own host and store ch a sub path. Store
+// codes in the URL are enabled for the website euro.
+func newResolverManager(t *testing.T) *store.Manager {
+	return newResolverManagerConfig(t, newResolverConfig(t))
+}
+
+// newResolverConfig writes the base URLs of the resolver fixture.
+func newResolverConfig(t *testing.T) *config.Manager {
+	cm := config.NewManager()
+	writeResolverConfig(t, cm)
+	return cm
+}
+
+func writeResolverConfig(t *testing.T, cm *config.Manager) {
+	for _, w := range []struct {
+		path, value string
+		scope       config.ArgFunc
+	}{
+		{store.PathUnsecureBaseURL, "http://euro.cs.io/", config.Scope(config.ScopeDefaultID, nil)},
+		{store.PathSecureBaseURL, "https://euro.cs.io/", config.Scope(config.ScopeDefaultID, nil)},
+		{store.PathUnsecureBaseURL, "http://oz.cs.io/", config.ScopeWebsite(config.ScopeID(2))},
+		{store.PathSecureBaseURL, "https://secure.oz.cs.io/", config.ScopeWebsite(config.ScopeID(2))},
+		{store.PathUnsecureBaseURL, "http://uk.cs.io/", config.ScopeStore(config.ScopeID(4))},
+		{store.PathSecureBaseURL, "https://uk.cs.io/", config.ScopeStore(config.ScopeID(4))},
+		{store.PathUnsecureBaseURL, "http://euro.cs.io/shop/", config.ScopeStore(config.ScopeID(3))},
+		{store.PathStoreInURL, "1", config.ScopeWebsite(config.ScopeID(1))},
+	} {
+		if err := cm.Write(config.Path(w.path), config.Value(w.value), w.scope, config.NoBubble()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newResolverManagerConfig(t *testing.T, cm *config.Manager) *store.Manager {
+	sm := store.NewManager(store.SetManagerConfig(cm), store.SetManagerStorage(newResolverStorage(cm)))
+	if err := sm.Init(config.ScopeCode("de"), config.ScopeStoreID); err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
+
+func TestResolveByHost(t *testing.T) {
+	sm := newResolverManager(t)
+	tests := []struct {
+		req      *http.Request
+		wantCode config.ScopeIDer
+	}{
+		{getTestRequest(t, "GET", "http://euro.cs.io/catalog", nil), config.ScopeCode("at")}, // default store of the website
+		{getTestRequest(t, "GET", "http://euro.cs.io/", &http.Cookie{Name: store.CookieName, Value: "de"}), config.ScopeCode("de")},
+		{getTestRequest(t, "GET", "http://euro.cs.io/", &http.Cookie{Name: store.CookieName, Value: "nz"}), config.ScopeCode("at")},
+		{getTestRequest(t, "GET", "http://UK.cs.io/", nil), config.ScopeCode("uk")},
+		{getTestRequest(t, "GET", "http://oz.cs.io/", nil), config.ScopeCode("au")},
+		{getTestRequest(t, "GET", "https://secure.oz.cs.io/", &http.Cookie{Name: store.CookieName, Value: "nz"}), config.ScopeCode("nz")},
+		{getTestRequest(t, "GET", "http://cs.io/", nil), nil},
+	}
+	for i, test := range tests {
+		have, err := store.ResolveByHost(sm, test.req)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantCode, have, "Index %d", i)
+	}
+}
+
+func TestResolveByHostCache(t *testing.T) {
+	// Init does not need the base URLs and an empty host map won't be cached
+	cm := config.NewManager()
+	sm := newResolverManagerConfig(t, cm)
+	have, err := store.ResolveByHost(sm, getTestRequest(t, "GET", "http://uk.cs.io/", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, have)
+
+	writeResolverConfig(t, cm)
+	have, err = store.ResolveByHost(sm, getTestRequest(t, "GET", "http://uk.cs.io/", nil))
+	assert.NoError(t, err)
+	assert.Exactly(t, config.ScopeCode("uk"), have)
+
+	// a written base URL resets the hosts
+	if err := cm.Write(config.Path(store.PathUnsecureBaseURL), config.Value("http://gb.cs.io/"), config.ScopeStore(config.ScopeID(4)), config.NoBubble()); err != nil {
+		t.Fatal(err)
+	}
+	have, err = store.ResolveByHost(sm, getTestRequest(t, "GET", "http://gb.cs.io/", nil))
+	assert.NoError(t, err)
+	assert.Exactly(t, config.ScopeCode("uk"), have)
+
+	sm.ClearCache()
+	have, err = store.ResolveByHost(sm, getTestRequest(t, "GET", "http://gb.cs.io/", nil))
+	assert.NoError(t, err)
+	assert.Exactly(t, config.ScopeCode("uk"), have)
+}
+
+func TestResolveByPathPrefix(t *testing.T) {
+	sm := newResolverManager(t)
+	tests := []struct {
+		path     string
+		wantCode config.ScopeIDer
+	}{
+		{"/de/catalog/product", config.ScopeCode("de")},
+		{"/uk", config.ScopeCode("uk")},
+		{"/shop/ch/", config.ScopeCode("ch")},
+		{"/ch/", nil},  // ch runs in /shop/
+		{"/nz/", nil},  // not enabled in the website oz
+		{"/dex/", nil}, // no prefix of a store code
+		{"/admin/", nil},
+		{"/", nil},
+	}
+	for i, test := range tests {
+		have, err := store.ResolveByPathPrefix(sm, getTestRequest(t, "GET", "http://euro.cs.io"+test.path, nil))
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantCode, have, "Index %d", i)
+	}
+}
+
+func TestInitByResolvers(t *testing.T) {
+	sm := newResolverManager(t)
+	tests := []struct {
+		req       *http.Request
+		scopeType config.ScopeGroup
+		wantCode  string
+		wantErr   error
+	}{
+		{getTestRequest(t, "GET", "http://oz.cs.io/de/", nil), config.ScopeWebsiteID, "de", nil},
+		{getTestRequest(t, "GET", "http://oz.cs.io/", nil), config.ScopeWebsiteID, "", store.ErrStoreChangeNotAllowed},
+		{getTestRequest(t, "GET", "http://oz.cs.io/", nil), config.ScopeStoreID, "au", nil},
+		{getTestRequest(t, "GET", "http://uk.cs.io/", nil), config.ScopeGroupID, "", store.ErrStoreChangeNotAllowed},
+		{getTestRequest(t, "GET", "http://uk.cs.io/", nil), config.ScopeWebsiteID, "uk", nil},
+		{getTestRequest(t, "GET", "http://cs.io/", nil), config.ScopeWebsiteID, "", nil},
+	}
+	for i, test := range tests {
+		have, err := sm.InitByResolvers(test.req, test.scopeType, store.ResolveByPathPrefix, nil, store.ResolveByHost)
+		if test.wantErr != nil {
+			assert.Nil(t, have, "Index %d", i)
+			assert.EqualError(t, err, test.wantErr.Error(), "Index %d", i)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		if test.wantCode == "" {
+			assert.Nil(t, have, "Index %d", i)
+			continue
+		}
+		assert.Exactly(t, test.wantCode, have.Data().Code.String, "Index %d", i)
+	}
+
+	_, err := store.NewManager().InitByResolvers(getTestRequest(t, "GET", "http://cs.io/", nil), config.ScopeStoreID, store.ResolveByHost)
+	assert.EqualError(t, err, store.ErrAppStoreNotSet.Error())
+}
+
+func TestStoreMiddlewareResolvers(t *testing.T) {
+	sm := newResolverManager(t)
+	mw := store.NewStoreMiddleware(sm, config.ScopeWebsiteID, store.SetMiddlewareResolvers(store.ResolveByPathPrefix, store.ResolveByHost))
+
+	tests := []struct {
+		req        *http.Request
+		wantCode   string
+		wantCookie string
+	}{
+		{getTestRequest(t, "GET", "http://euro.cs.io/uk/", &http.Cookie{Name: store.CookieName, Value: "de"}), "uk", store.CookieName + "=uk;"},
+		// at is the default store of the website euro
+		{getTestRequest(t, "GET", "http://euro.cs.io/?"+store.HTTPRequestParamStore+"=ch", nil), "at", store.CookieName + "=;"},
+		{getTestRequest(t, "GET", "http://oz.cs.io/?"+store.HTTPRequestParamStore+"=ch", nil), "ch", store.CookieName + "=ch;"}, // au not allowed
+		{getTestRequest(t, "GET", "http://cs.io/", nil), "de", ""},
+	}
+	for i, test := range tests {
+		rec := httptest.NewRecorder()
+		mw.Handle(middlewareStoreCode).ServeHTTP(rec, test.req)
+		assert.Exactly(t, test.wantCode, rec.Body.String(), "Index %d", i)
+		if test.wantCookie != "" {
+			assert.Contains(t, rec.Header().Get("Set-Cookie"), test.wantCookie, "Index %d", i)
+		} else {
+			assert.Empty(t, rec.Header().Get("Set-Cookie"), "Index %d", i)
+		}
+	}
+}
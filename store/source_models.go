@@ -0,0 +1,85 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"strconv"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/utils/log"
+)
+
+type (
+	// SourceStore lists all store views of a Manager without the admin store.
+	// Magento\Config\Model\Config\Source\Store
+	SourceStore struct {
+		sm *Manager
+	}
+
+	// SourceWebsite lists all websites of a Manager without the admin website.
+	// Magento\Config\Model\Config\Source\Website
+	SourceWebsite struct {
+		sm *Manager
+	}
+)
+
+var (
+	_ config.FieldSourceModeller = (*SourceStore)(nil)
+	_ config.FieldSourceModeller = (*SourceWebsite)(nil)
+)
+
+// NewSourceStore creates a new option for the store views of the Manager.
+func NewSourceStore(sm *Manager) *SourceStore {
+	return &SourceStore{sm: sm}
+}
+
+// Options returns the store ID as value and the name as label. Errors will be logged.
+func (ss *SourceStore) Options() config.ValueLabelSlice {
+	stores, err := ss.sm.Stores()
+	if err != nil {
+		log.Error("store.SourceStore.Options", "err", err)
+		return nil
+	}
+	vls := make(config.ValueLabelSlice, 0, stores.Len())
+	for _, s := range stores {
+		if s.ScopeID() == DefaultStoreID {
+			continue
+		}
+		vls = append(vls, config.ValueLabel{Value: strconv.FormatInt(s.ScopeID(), 10), Label: s.Data().Name})
+	}
+	return vls
+}
+
+// NewSourceWebsite creates a new option for the websites of the Manager.
+func NewSourceWebsite(sm *Manager) *SourceWebsite {
+	return &SourceWebsite{sm: sm}
+}
+
+// Options returns the website ID as value and the name as label. Errors will be logged.
+func (sw *SourceWebsite) Options() config.ValueLabelSlice {
+	websites, err := sw.sm.Websites()
+	if err != nil {
+		log.Error("store.SourceWebsite.Options", "err", err)
+		return nil
+	}
+	vls := make(config.ValueLabelSlice, 0, len(websites))
+	for _, w := range websites {
+		if w.ScopeID() == DefaultWebsiteID {
+			continue
+		}
+		vls = append(vls, config.ValueLabel{Value: strconv.FormatInt(w.ScopeID(), 10), Label: w.Data().Name.String})
+	}
+	return vls
+}
@@ -0,0 +1,400 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"errors"
+
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/juju/errgo"
+)
+
+var (
+	// ErrStorageNotMutable gets returned when the Storager of the Manager does
+	// not implement the StorageMutator.
+	ErrStorageNotMutable = errors.New("Storage cannot be mutated")
+	// ErrWebsiteCodeExists another website uses the same code.
+	ErrWebsiteCodeExists = errors.New("Website code already exists")
+	// ErrWebsiteDefaultExists another website is already the default one.
+	ErrWebsiteDefaultExists = errors.New("Default website already exists")
+	// ErrStoreCodeExists another store uses the same code.
+	ErrStoreCodeExists = errors.New("Store code already exists")
+	// ErrDeleteDefault the default website, the default group of a website
+	// or the default store of a group cannot be deleted.
+	ErrDeleteDefault = errors.New("A default cannot be deleted")
+	// ErrDeleteAdmin the admin website, group or store with ID 0 cannot be deleted.
+	ErrDeleteAdmin = errors.New("The admin scope cannot be deleted")
+)
+
+// StorageMutator creates, updates and deletes websites, groups and stores in
+// the database. The Storager of the Manager must implement it to be able to
+// persist changes via the Manager. The in-memory data will be changed right
+// after the statement so that further changes within the same transaction
+// get validated against it. Use Manager.Mutate() to persist several changes
+// atomically, it reloads the data after a rollback.
+type StorageMutator interface {
+	// CreateWebsite inserts the website and sets its new ID.
+	CreateWebsite(dbr.SessionRunner, *TableWebsite) error
+	// UpdateWebsite updates an existing website.
+	UpdateWebsite(dbr.SessionRunner, *TableWebsite) error
+	// DeleteWebsite deletes a website with all its groups and stores.
+	DeleteWebsite(dbrSess dbr.SessionRunner, websiteID int64) error
+	// CreateGroup inserts the group and sets its new ID.
+	CreateGroup(dbr.SessionRunner, *TableGroup) error
+	// UpdateGroup updates an existing group.
+	UpdateGroup(dbr.SessionRunner, *TableGroup) error
+	// DeleteGroup deletes a group with all its stores.
+	DeleteGroup(dbrSess dbr.SessionRunner, groupID int64) error
+	// CreateStore inserts the store and sets its new ID.
+	CreateStore(dbr.SessionRunner, *TableStore) error
+	// UpdateStore updates an existing store.
+	UpdateStore(dbr.SessionRunner, *TableStore) error
+	// DeleteStore deletes a store.
+	DeleteStore(dbrSess dbr.SessionRunner, storeID int64) error
+}
+
+var _ StorageMutator = (*Storage)(nil)
+
+// CreateWebsite validates and inserts the website and sets its new ID.
+func (st *Storage) CreateWebsite(dbrSess dbr.SessionRunner, tw *TableWebsite) error {
+	if tw == nil {
+		return ErrStoreNewArgNil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.validateWebsite(tw, true); err != nil {
+		return err
+	}
+	res, err := dbrSess.
+		InsertInto(TableCollection.Name(TableIndexWebsite)).
+		Columns("code", "name", "sort_order", "default_group_id", "is_default").
+		Values(tw.Code, tw.Name, tw.SortOrder, tw.DefaultGroupID, tw.IsDefault).
+		Exec()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	if tw.WebsiteID, err = res.LastInsertId(); err != nil {
+		return errgo.Mask(err)
+	}
+	c := *tw
+	st.websites = append(st.websites, &c)
+	return nil
+}
+
+// UpdateWebsite validates and updates an existing website.
+func (st *Storage) UpdateWebsite(dbrSess dbr.SessionRunner, tw *TableWebsite) error {
+	if tw == nil {
+		return ErrStoreNewArgNil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	old, err := st.websites.FindByID(tw.WebsiteID)
+	if err != nil {
+		return err
+	}
+	if err := st.validateWebsite(tw, false); err != nil {
+		return err
+	}
+	if _, err := dbrSess.
+		Update(TableCollection.Name(TableIndexWebsite)).
+		Set("code", tw.Code).
+		Set("name", tw.Name).
+		Set("sort_order", tw.SortOrder).
+		Set("default_group_id", tw.DefaultGroupID).
+		Set("is_default", tw.IsDefault).
+		Where("website_id = ?", tw.WebsiteID).
+		Exec(); err != nil {
+		return errgo.Mask(err)
+	}
+	*old = *tw
+	return nil
+}
+
+// DeleteWebsite deletes a website and removes all its groups and stores. The
+// database removes them via its foreign keys. Returns ErrDeleteDefault for
+// the default website and ErrDeleteAdmin for the admin website.
+func (st *Storage) DeleteWebsite(dbrSess dbr.SessionRunner, websiteID int64) error {
+	if websiteID == 0 {
+		return ErrDeleteAdmin
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tw, err := st.websites.FindByID(websiteID)
+	if err != nil {
+		return err
+	}
+	if tw.IsDefault.Valid && tw.IsDefault.Bool {
+		return ErrDeleteDefault
+	}
+	if _, err := dbrSess.
+		DeleteFrom(TableCollection.Name(TableIndexWebsite)).
+		Where("website_id = ?", websiteID).
+		Exec(); err != nil {
+		return errgo.Mask(err)
+	}
+	st.websites = st.websites.Filter(func(w *TableWebsite) bool { return w.WebsiteID != websiteID })
+	st.groups = st.groups.Filter(func(g *TableGroup) bool { return g.WebsiteID != websiteID })
+	st.stores = st.stores.Filter(func(s *TableStore) bool { return s.WebsiteID != websiteID })
+	return nil
+}
+
+// CreateGroup validates and inserts the group and sets its new ID.
+func (st *Storage) CreateGroup(dbrSess dbr.SessionRunner, tg *TableGroup) error {
+	if tg == nil {
+		return ErrStoreNewArgNil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.validateGroup(tg, true); err != nil {
+		return err
+	}
+	res, err := dbrSess.
+		InsertInto(TableCollection.Name(TableIndexGroup)).
+		Columns("website_id", "name", "root_category_id", "default_store_id").
+		Values(tg.WebsiteID, tg.Name, tg.RootCategoryID, tg.DefaultStoreID).
+		Exec()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	if tg.GroupID, err = res.LastInsertId(); err != nil {
+		return errgo.Mask(err)
+	}
+	c := *tg
+	st.groups = append(st.groups, &c)
+	return nil
+}
+
+// UpdateGroup validates and updates an existing group.
+func (st *Storage) UpdateGroup(dbrSess dbr.SessionRunner, tg *TableGroup) error {
+	if tg == nil {
+		return ErrStoreNewArgNil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	old, err := st.groups.FindByID(tg.GroupID)
+	if err != nil {
+		return err
+	}
+	if err := st.validateGroup(tg, false); err != nil {
+		return err
+	}
+	if _, err := dbrSess.
+		Update(TableCollection.Name(TableIndexGroup)).
+		Set("website_id", tg.WebsiteID).
+		Set("name", tg.Name).
+		Set("root_category_id", tg.RootCategoryID).
+		Set("default_store_id", tg.DefaultStoreID).
+		Where("group_id = ?", tg.GroupID).
+		Exec(); err != nil {
+		return errgo.Mask(err)
+	}
+	*old = *tg
+	return nil
+}
+
+// DeleteGroup deletes a group and removes all its stores. The database
+// removes them via its foreign keys. Returns ErrDeleteDefault for the default
+// group of a website and ErrDeleteAdmin for the admin group.
+func (st *Storage) DeleteGroup(dbrSess dbr.SessionRunner, groupID int64) error {
+	if groupID == 0 {
+		return ErrDeleteAdmin
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	tg, err := st.groups.FindByID(groupID)
+	if err != nil {
+		return err
+	}
+	if tw, err := st.websites.FindByID(tg.WebsiteID); err == nil && tw.DefaultGroupID == groupID {
+		return ErrDeleteDefault
+	}
+	if _, err := dbrSess.
+		DeleteFrom(TableCollection.Name(TableIndexGroup)).
+		Where("group_id = ?", groupID).
+		Exec(); err != nil {
+		return errgo.Mask(err)
+	}
+	st.groups = st.groups.Filter(func(g *TableGroup) bool { return g.GroupID != groupID })
+	st.stores = st.stores.Filter(func(s *TableStore) bool { return s.GroupID != groupID })
+	return nil
+}
+
+// CreateStore validates and inserts the store and sets its new ID.
+func (st *Storage) CreateStore(dbrSess dbr.SessionRunner, ts *TableStore) error {
+	if ts == nil {
+		return ErrStoreNewArgNil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if err := st.validateStore(ts, true); err != nil {
+		return err
+	}
+	res, err := dbrSess.
+		InsertInto(TableCollection.Name(TableIndexStore)).
+		Columns("code", "website_id", "group_id", "name", "sort_order", "is_active").
+		Values(ts.Code, ts.WebsiteID, ts.GroupID, ts.Name, ts.SortOrder, ts.IsActive).
+		Exec()
+	if err != nil {
+		return errgo.Mask(err)
+	}
+	if ts.StoreID, err = res.LastInsertId(); err != nil {
+		return errgo.Mask(err)
+	}
+	c := *ts
+	st.stores = append(st.stores, &c)
+	return nil
+}
+
+// UpdateStore validates and updates an existing store.
+func (st *Storage) UpdateStore(dbrSess dbr.SessionRunner, ts *TableStore) error {
+	if ts == nil {
+		return ErrStoreNewArgNil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	old, err := st.stores.FindByID(ts.StoreID)
+	if err != nil {
+		return err
+	}
+	if err := st.validateStore(ts, false); err != nil {
+		return err
+	}
+	if _, err := dbrSess.
+		Update(TableCollection.Name(TableIndexStore)).
+		Set("code", ts.Code).
+		Set("website_id", ts.WebsiteID).
+		Set("group_id", ts.GroupID).
+		Set("name", ts.Name).
+		Set("sort_order", ts.SortOrder).
+		Set("is_active", ts.IsActive).
+		Where("store_id = ?", ts.StoreID).
+		Exec(); err != nil {
+		return errgo.Mask(err)
+	}
+	*old = *ts
+	return nil
+}
+
+// DeleteStore deletes a store. Returns ErrDeleteDefault for the default store
+// of a group and ErrDeleteAdmin for the admin store.
+func (st *Storage) DeleteStore(dbrSess dbr.SessionRunner, storeID int64) error {
+	if storeID == 0 {
+		return ErrDeleteAdmin
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	ts, err := st.stores.FindByID(storeID)
+	if err != nil {
+		return err
+	}
+	if tg, err := st.groups.FindByID(ts.GroupID); err == nil && tg.DefaultStoreID == storeID {
+		return ErrDeleteDefault
+	}
+	if _, err := dbrSess.
+		DeleteFrom(TableCollection.Name(TableIndexStore)).
+		Where("store_id = ?", storeID).
+		Exec(); err != nil {
+		return errgo.Mask(err)
+	}
+	st.stores = st.stores.Filter(func(s *TableStore) bool { return s.StoreID != storeID })
+	return nil
+}
+
+// validateWebsite checks the code, that there is only one default website
+// and that the default group belongs to the website.
+func (st *Storage) validateWebsite(tw *TableWebsite, isNew bool) error {
+	if err := ValidateStoreCode(tw.Code.String); err != nil {
+		return err
+	}
+	for _, w := range st.websites {
+		if w == nil || (false == isNew && w.WebsiteID == tw.WebsiteID) {
+			continue
+		}
+		if w.Code.Valid && w.Code.String == tw.Code.String {
+			return ErrWebsiteCodeExists
+		}
+		if tw.IsDefault.Valid && tw.IsDefault.Bool && w.IsDefault.Valid && w.IsDefault.Bool {
+			return ErrWebsiteDefaultExists
+		}
+	}
+	if tw.DefaultGroupID > 0 {
+		if g, err := st.groups.FindByID(tw.DefaultGroupID); err != nil || isNew || g.WebsiteID != tw.WebsiteID {
+			return ErrWebsiteDefaultGroupNotFound
+		}
+	}
+	return nil
+}
+
+// validateGroup checks that the website exists, that the default store
+// belongs to the group and that all stores of the group belong to the website.
+// The default group of a website cannot move to another website.
+func (st *Storage) validateGroup(tg *TableGroup, isNew bool) error {
+	if _, err := st.websites.FindByID(tg.WebsiteID); err != nil {
+		return ErrGroupWebsiteNotFound
+	}
+	if isNew {
+		if tg.DefaultStoreID > 0 {
+			return ErrGroupDefaultStoreNotFound
+		}
+		return nil
+	}
+	if old, err := st.groups.FindByID(tg.GroupID); err == nil && old.WebsiteID != tg.WebsiteID {
+		if w, err := st.websites.FindByID(old.WebsiteID); err == nil && w.DefaultGroupID == tg.GroupID {
+			return ErrWebsiteDefaultGroupNotFound
+		}
+	}
+	if tg.DefaultStoreID > 0 {
+		if s, err := st.stores.FindByID(tg.DefaultStoreID); err != nil || s.GroupID != tg.GroupID {
+			return ErrGroupDefaultStoreNotFound
+		}
+	}
+	for _, s := range st.stores.FilterByGroupID(tg.GroupID) {
+		if s.WebsiteID != tg.WebsiteID {
+			return ErrStoreIncorrectWebsite
+		}
+	}
+	return nil
+}
+
+// validateStore checks the code and the same integrity of the website and
+// the group as NewStore(). The default store of a group cannot be deactivated
+// or moved to another group.
+func (st *Storage) validateStore(ts *TableStore, isNew bool) error {
+	if err := ValidateStoreCode(ts.Code.String); err != nil {
+		return err
+	}
+	if s, err := st.stores.FindByCode(ts.Code.String); err == nil && (isNew || s.StoreID != ts.StoreID) {
+		return ErrStoreCodeExists
+	}
+	if _, err := st.websites.FindByID(ts.WebsiteID); err != nil {
+		return ErrStoreIncorrectWebsite
+	}
+	g, err := st.groups.FindByID(ts.GroupID)
+	if err != nil || g.WebsiteID != ts.WebsiteID {
+		return ErrStoreIncorrectGroup
+	}
+	if isNew {
+		return nil
+	}
+	if g.DefaultStoreID == ts.StoreID && false == ts.IsActive {
+		return ErrStoreNotActive
+	}
+	if old, err := st.stores.FindByID(ts.StoreID); err == nil && old.GroupID != ts.GroupID {
+		if og, err := st.groups.FindByID(old.GroupID); err == nil && og.DefaultStoreID == ts.StoreID {
+			return ErrGroupDefaultStoreNotFound
+		}
+	}
+	return nil
+}
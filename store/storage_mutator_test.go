@@ -0,0 +1,176 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func nullString(s string) dbr.NullString {
+	return dbr.NullString{NullString: sql.NullString{String: s, Valid: true}}
+}
+
+// newMutatorStorage creates a new Storage for each test because the mutations
+// change the slices.
+func newMutatorStorage() *store.Storage {
+	return store.NewStorage(
+		store.SetStorageWebsites(
+			&store.TableWebsite{WebsiteID: 0, Code: nullString("admin"), Name: nullString("Admin"), SortOrder: 0, DefaultGroupID: 0, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: false, Valid: true}}},
+			&store.TableWebsite{WebsiteID: 1, Code: nullString("euro"), Name: nullString("Europe"), SortOrder: 0, DefaultGroupID: 1, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: true, Valid: true}}},
+			&store.TableWebsite{WebsiteID: 2, Code: nullString("oz"), Name: nullString("OZ"), SortOrder: 20, DefaultGroupID: 3, IsDefault: dbr.NullBool{NullBool: sql.NullBool{Bool: false, Valid: true}}},
+		),
+		store.SetStorageGroups(
+			&store.TableGroup{GroupID: 3, WebsiteID: 2, Name: "Australia", RootCategoryID: 2, DefaultStoreID: 5},
+			&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 2, DefaultStoreID: 2},
+			&store.TableGroup{GroupID: 0, WebsiteID: 0, Name: "Default", RootCategoryID: 0, DefaultStoreID: 0},
+			&store.TableGroup{GroupID: 2, WebsiteID: 1, Name: "UK Group", RootCategoryID: 2, DefaultStoreID: 4},
+		),
+		store.SetStorageStores(
+			&store.TableStore{StoreID: 0, Code: nullString("admin"), WebsiteID: 0, GroupID: 0, Name: "Admin", SortOrder: 0, IsActive: true},
+			&store.TableStore{StoreID: 5, Code: nullString("au"), WebsiteID: 2, GroupID: 3, Name: "Australia", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 1, Code: nullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 4, Code: nullString("uk"), WebsiteID: 1, GroupID: 2, Name: "UK", SortOrder: 10, IsActive: true},
+			&store.TableStore{StoreID: 2, Code: nullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", SortOrder: 20, IsActive: true},
+			&store.TableStore{StoreID: 6, Code: nullString("nz"), WebsiteID: 2, GroupID: 3, Name: "Kiwi", SortOrder: 30, IsActive: true},
+			&store.TableStore{StoreID: 3, Code: nullString("ch"), WebsiteID: 1, GroupID: 1, Name: "Schweiz", SortOrder: 30, IsActive: true},
+		),
+	)
+}
+
+func TestStorageMutatorValidation(t *testing.T) {
+	// the database connection will never be touched because all mutations fail before
+	sess := dbr.NewConnection(nil, nil).NewSession(nil)
+	st := newMutatorStorage()
+	isDefault := dbr.NullBool{NullBool: sql.NullBool{Bool: true, Valid: true}}
+
+	tests := []struct {
+		err     error
+		wantErr error
+	}{
+		{st.CreateWebsite(sess, nil), store.ErrStoreNewArgNil},
+		{st.CreateWebsite(sess, &store.TableWebsite{Code: nullString("1euro")}), store.ErrStoreCodeInvalid},
+		{st.CreateWebsite(sess, &store.TableWebsite{Code: nullString("oz")}), store.ErrWebsiteCodeExists},
+		{st.CreateWebsite(sess, &store.TableWebsite{Code: nullString("asia"), IsDefault: isDefault}), store.ErrWebsiteDefaultExists},
+		{st.CreateWebsite(sess, &store.TableWebsite{Code: nullString("asia"), DefaultGroupID: 1}), store.ErrWebsiteDefaultGroupNotFound},
+		{st.UpdateWebsite(sess, &store.TableWebsite{WebsiteID: 9, Code: nullString("asia")}), store.ErrWebsiteNotFound},
+		{st.UpdateWebsite(sess, &store.TableWebsite{WebsiteID: 2, Code: nullString("oz"), DefaultGroupID: 3, IsDefault: isDefault}), store.ErrWebsiteDefaultExists},
+		{st.UpdateWebsite(sess, &store.TableWebsite{WebsiteID: 2, Code: nullString("oz"), DefaultGroupID: 1}), store.ErrWebsiteDefaultGroupNotFound},
+		{st.DeleteWebsite(sess, 1), store.ErrDeleteDefault},
+		{st.DeleteWebsite(sess, 9), store.ErrWebsiteNotFound},
+		{st.DeleteWebsite(sess, 0), store.ErrDeleteAdmin},
+
+		{st.CreateGroup(sess, &store.TableGroup{WebsiteID: 9, Name: "Asia"}), store.ErrGroupWebsiteNotFound},
+		{st.CreateGroup(sess, &store.TableGroup{WebsiteID: 2, Name: "NZ", DefaultStoreID: 6}), store.ErrGroupDefaultStoreNotFound},
+		{st.UpdateGroup(sess, &store.TableGroup{GroupID: 2, WebsiteID: 1, Name: "UK", DefaultStoreID: 1}), store.ErrGroupDefaultStoreNotFound},
+		{st.UpdateGroup(sess, &store.TableGroup{GroupID: 2, WebsiteID: 2, Name: "UK", DefaultStoreID: 4}), store.ErrStoreIncorrectWebsite},
+		{st.UpdateGroup(sess, &store.TableGroup{GroupID: 3, WebsiteID: 1, Name: "Australia", DefaultStoreID: 5}), store.ErrWebsiteDefaultGroupNotFound},
+		{st.DeleteGroup(sess, 1), store.ErrDeleteDefault},
+		{st.DeleteGroup(sess, 9), store.ErrGroupNotFound},
+		{st.DeleteGroup(sess, 0), store.ErrDeleteAdmin},
+
+		{st.CreateStore(sess, &store.TableStore{Code: nullString("_fr"), WebsiteID: 1, GroupID: 1}), store.ErrStoreCodeInvalid},
+		{st.CreateStore(sess, &store.TableStore{Code: nullString("de"), WebsiteID: 1, GroupID: 1}), store.ErrStoreCodeExists},
+		{st.CreateStore(sess, &store.TableStore{Code: nullString("fr"), WebsiteID: 9, GroupID: 1}), store.ErrStoreIncorrectWebsite},
+		{st.CreateStore(sess, &store.TableStore{Code: nullString("fr"), WebsiteID: 2, GroupID: 1}), store.ErrStoreIncorrectGroup},
+		{st.UpdateStore(sess, &store.TableStore{StoreID: 1, Code: nullString("at"), WebsiteID: 1, GroupID: 1}), store.ErrStoreCodeExists},
+		{st.UpdateStore(sess, &store.TableStore{StoreID: 2, Code: nullString("at"), WebsiteID: 1, GroupID: 1, IsActive: false}), store.ErrStoreNotActive},
+		{st.UpdateStore(sess, &store.TableStore{StoreID: 2, Code: nullString("at"), WebsiteID: 1, GroupID: 2, IsActive: true}), store.ErrGroupDefaultStoreNotFound},
+		{st.DeleteStore(sess, 2), store.ErrDeleteDefault},
+		{st.DeleteStore(sess, 9), store.ErrStoreNotFound},
+		{st.DeleteStore(sess, 0), store.ErrDeleteAdmin},
+	}
+	for i, test := range tests {
+		assert.EqualError(t, test.err, test.wantErr.Error(), "Index %d", i)
+	}
+}
+
+func TestManagerMutateNotMutable(t *testing.T) {
+	sess := dbr.NewConnection(nil, nil).NewSession(nil)
+	sm := getTestManager()
+	assert.EqualError(t, sm.CreateWebsite(sess, &store.TableWebsite{}), store.ErrStorageNotMutable.Error())
+	assert.EqualError(t, sm.DeleteStore(sess, 1), store.ErrStorageNotMutable.Error())
+}
+
+func TestManagerMutateDB(t *testing.T) {
+	db := csdb.MustConnectTest()
+	defer db.Close()
+	tx, err := dbr.NewConnection(db, nil).NewSession(nil).Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	cm := config.NewManager()
+	st := store.NewStorage(store.SetStorageConfig(cm))
+	assert.NoError(t, st.ReInit(tx))
+	sm := store.NewManager(store.SetManagerStorage(st), store.SetManagerConfig(cm))
+
+	tw := &store.TableWebsite{Code: nullString("csfwtest"), Name: nullString("Test")}
+	assert.NoError(t, sm.CreateWebsite(tx, tw))
+	assert.True(t, tw.WebsiteID > 0)
+	tg := &store.TableGroup{WebsiteID: tw.WebsiteID, Name: "Test Group", RootCategoryID: 2}
+	assert.NoError(t, sm.CreateGroup(tx, tg))
+	ts := &store.TableStore{Code: nullString("csfwtest"), WebsiteID: tw.WebsiteID, GroupID: tg.GroupID, Name: "Test Store", IsActive: true}
+	assert.NoError(t, sm.CreateStore(tx, ts))
+
+	tg.DefaultStoreID = ts.StoreID
+	assert.NoError(t, sm.UpdateGroup(tx, tg))
+	tw.DefaultGroupID = tg.GroupID
+	assert.NoError(t, sm.UpdateWebsite(tx, tw))
+
+	s, err := sm.Store(config.ScopeCode("csfwtest"))
+	assert.NoError(t, err)
+	assert.Exactly(t, tw.WebsiteID, s.WebsiteID())
+	g, err := sm.Group(config.ScopeID(tg.GroupID))
+	assert.NoError(t, err)
+	assert.Exactly(t, ts.StoreID, g.Data().DefaultStoreID)
+
+	assert.EqualError(t, sm.DeleteStore(tx, ts.StoreID), store.ErrDeleteDefault.Error())
+	assert.NoError(t, sm.DeleteWebsite(tx, tw.WebsiteID))
+	_, err = sm.Store(config.ScopeCode("csfwtest"))
+	assert.EqualError(t, err, store.ErrStoreNotFound.Error())
+}
+
+func TestManagerMutateRollbackDB(t *testing.T) {
+	db := csdb.MustConnectTest()
+	defer db.Close()
+	sess := dbr.NewConnection(db, nil).NewSession(nil)
+
+	cm := config.NewManager()
+	st := store.NewStorage(store.SetStorageConfig(cm))
+	assert.NoError(t, st.ReInit(sess))
+	sm := store.NewManager(store.SetManagerStorage(st), store.SetManagerConfig(cm))
+
+	errAbort := errors.New("Abort")
+	err := sm.Mutate(sess, func(tx *dbr.Tx) error {
+		if err := sm.CreateWebsite(tx, &store.TableWebsite{Code: nullString("csfwtest"), Name: nullString("Test")}); err != nil {
+			return err
+		}
+		_, err := sm.Website(config.ScopeCode("csfwtest"))
+		assert.NoError(t, err, "the website must be visible within the transaction")
+		return errAbort
+	})
+	assert.Exactly(t, errAbort, err)
+	_, err = sm.Website(config.ScopeCode("csfwtest"))
+	assert.EqualError(t, err, store.ErrWebsiteNotFound.Error(), "the rolled back website must be removed")
+}
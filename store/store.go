@@ -27,6 +27,7 @@ import (
 	"github.com/corestoreio/csfw/storage/csdb"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/utils"
+	"github.com/corestoreio/csfw/utils/log"
 	"github.com/dgrijalva/jwt-go"
 )
 
@@ -52,6 +53,8 @@ type (
 	// which overrides the default scope and website scope.
 	Store struct {
 		cr config.Reader
+		// res expands the placeholders in configuration values
+		res *config.Resolver
 		// Contains the current website for this store. No integrity checks
 		w *Website
 		g *Group
@@ -76,6 +79,7 @@ var (
 
 var _ config.ScopeIDer = (*Store)(nil)
 var _ config.ScopeCoder = (*Store)(nil)
+var _ config.WebsiteIDer = (*Store)(nil)
 
 // SetStoreConfig sets the config.Reader to the Store.
 // Default reader is config.DefaultManager
@@ -107,16 +111,73 @@ func NewStore(ts *TableStore, tw *TableWebsite, tg *TableGroup, opts ...StoreOpt
 	return s
 }
 
-// ApplyOptions sets the options to the Store struct.
+// ApplyOptions sets the options to the Store struct. Placeholders registered
+// via Resolver().Register() will be kept.
 func (s *Store) ApplyOptions(opts ...StoreOption) *Store {
 	for _, opt := range opts {
 		if opt != nil {
 			opt(s)
 		}
 	}
+	switch {
+	case s.res == nil:
+		s.res = newResolver(s.cr)
+	case len(opts) > 0:
+		// the config.Reader may have changed
+		s.res = s.res.WithReader(s.cr)
+	}
 	return s
 }
 
+// newResolver creates a config.Resolver with the placeholders of the store package:
+// base_url, secure_base_url and unsecure_base_url.
+func newResolver(cr config.Reader) *config.Resolver {
+	return config.NewResolver(cr).
+		Register(placeholderName(PlaceholderBaseURL), placeholderBaseURL).
+		Register(placeholderName(PlaceholderBaseURLSecure), placeholderPath(PathSecureBaseURL)).
+		Register(placeholderName(PlaceholderBaseURLUnSecure), placeholderPath(PathUnsecureBaseURL))
+}
+
+// placeholderName removes the delimiters from a placeholder.
+func placeholderName(p string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(p, config.LeftDelim), config.RightDelim)
+}
+
+// placeholderBaseURL returns the CoreStore base URL from the default scope.
+// RequestBaseURL() replaces it with the distro base URL of the request.
+func placeholderBaseURL(cr config.Reader, _ ...config.ArgFunc) (string, error) {
+	return cr.String(config.Path(config.PathCSBaseURL))
+}
+
+// placeholderPath returns the value of path in the scope of the arguments.
+func placeholderPath(path string) config.PlaceholderProvider {
+	return func(cr config.Reader, o ...config.ArgFunc) (string, error) {
+		return cr.String(append(o, config.Path(path))...)
+	}
+}
+
+// placeholderDistroBaseURL returns the scheme and the host of the request if
+// DefaultAllowedHosts contains the host. Any other host falls back to
+// placeholderBaseURL() to prevent a poisoned Host header in the URLs.
+// @see \Magento\Framework\App\Request\Http::getDistroBaseUrl()
+func placeholderDistroBaseURL(r *http.Request, isSecure bool) config.PlaceholderProvider {
+	return func(cr config.Reader, o ...config.ArgFunc) (string, error) {
+		if r.Host == "" || false == DefaultAllowedHosts.Contains(r.Host) {
+			return placeholderBaseURL(cr, o...)
+		}
+		scheme := "http://"
+		if isSecure {
+			scheme = "https://"
+		}
+		return scheme + r.Host + "/", nil
+	}
+}
+
+// Resolver returns the config.Resolver of the Store to register custom placeholders.
+func (s *Store) Resolver() *config.Resolver {
+	return s.res
+}
+
 /*
 	@todo implement Magento\Store\Model\Store
 */
@@ -131,6 +192,12 @@ func (s *Store) ScopeCode() string {
 	return s.s.Code.String
 }
 
+// WebsiteID returns the ID of the website. Satisfies the interface config.WebsiteIDer
+// so that config lookups of the store fall back to the website scope.
+func (s *Store) WebsiteID() int64 {
+	return s.s.WebsiteID
+}
+
 // Website returns the website associated to this store
 func (s *Store) Website() *Website {
 	return s.w
@@ -158,6 +225,24 @@ func (s *Store) Path() string {
 // BaseUrl returns the path from the URL or config where CoreStore is installed @todo
 // @see https://github.com/magento/magento2/blob/0.74.0-beta7/app/code/Magento/Store/Model/Store.php#L539
 func (s *Store) BaseURL(ut config.URLType, isSecure bool) string {
+	return s.baseURL(s.res, ut, isSecure)
+}
+
+// RequestBaseURL returns like BaseURL() the web, static or media URL. The
+// secure URL will be returned if the request has been made with HTTPS and
+// secure URLs are enabled in the frontend (web/secure/use_in_frontend). HTTPS
+// behind a reverse proxy will be detected with DefaultTrustedProxies. The
+// placeholder {{base_url}} will be replaced with the scheme and host of the
+// request if DefaultAllowedHosts contains the host.
+func (s *Store) RequestBaseURL(r *http.Request, ut config.URLType) string {
+	isSecureReq := DefaultTrustedProxies.IsSecure(r)
+	isSecure := isSecureReq && s.cr.GetBool(config.ScopeStore(s), config.Path(PathSecureInFrontend))
+	res := s.res.Clone().Register(placeholderName(PlaceholderBaseURL), placeholderDistroBaseURL(r, isSecureReq))
+	return s.baseURL(res, ut, isSecure)
+}
+
+// baseURL expands the placeholders of the URL with res.
+func (s *Store) baseURL(res *config.Resolver, ut config.URLType, isSecure bool) string {
 	var url string
 	var p string
 	switch ut {
@@ -184,12 +269,11 @@ func (s *Store) BaseURL(ut config.URLType, isSecure bool) string {
 		panic("Unsupported UrlType")
 	}
 
-	url = s.ConfigString(p)
-
-	if strings.Contains(url, PlaceholderBaseURL) {
-		// @todo replace placeholder with \Magento\Framework\App\Request\Http::getDistroBaseUrl()
-		// getDistroBaseUrl will be generated from the $_SERVER variable,
-		url = strings.Replace(url, PlaceholderBaseURL, s.cr.GetString(config.Path(config.PathCSBaseURL)), 1)
+	raw := s.ConfigString(p)
+	url, err := res.Expand(raw, config.ScopeStore(s))
+	if err != nil {
+		log.Error("Store=BaseURL", "err", err, "path", p)
+		url = raw
 	}
 	url = strings.TrimRight(url, "/") + "/"
 
@@ -256,7 +340,7 @@ func (s *Store) RootCategoryId() int64 {
 
 // AllowedCurrencies returns all installed currencies from global scope.
 func (s *Store) AllowedCurrencies() []string {
-	return strings.Split(s.cr.GetString(config.Path(directory.PathSystemCurrencyInstalled)), ",")
+	return directory.InstalledCurrencies(s.cr)
 }
 
 // CurrentCurrency @todo
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/directory"
 	"github.com/corestoreio/csfw/storage/csdb"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/store"
@@ -250,7 +251,7 @@ func TestStoreBaseUrlandPath(t *testing.T) {
 		wantPath     string
 	}{
 		{
-			config.NewMockReader(func(path string) string {
+			config.NewMockReader(config.MockString(func(path string) string {
 				switch path {
 				case config.ScopeRangeDefault + "/0/" + store.PathSecureBaseURL:
 					return "https://corestore.io"
@@ -258,11 +259,11 @@ func TestStoreBaseUrlandPath(t *testing.T) {
 					return "http://corestore.io"
 				}
 				return ""
-			}, nil),
+			})),
 			config.URLTypeWeb, true, "https://corestore.io/", "/",
 		},
 		{
-			config.NewMockReader(func(path string) string {
+			config.NewMockReader(config.MockString(func(path string) string {
 				switch path {
 				case config.ScopeRangeDefault + "/0/" + store.PathSecureBaseURL:
 					return "https://myplatform.io/customer1"
@@ -270,11 +271,11 @@ func TestStoreBaseUrlandPath(t *testing.T) {
 					return "http://myplatform.io/customer1"
 				}
 				return ""
-			}, nil),
+			})),
 			config.URLTypeWeb, false, "http://myplatform.io/customer1/", "/customer1/",
 		},
 		{
-			config.NewMockReader(func(path string) string {
+			config.NewMockReader(config.MockString(func(path string) string {
 				switch path {
 				case config.ScopeRangeDefault + "/0/" + store.PathSecureBaseURL:
 					return store.PlaceholderBaseURL
@@ -284,7 +285,7 @@ func TestStoreBaseUrlandPath(t *testing.T) {
 					return config.CSBaseURL
 				}
 				return ""
-			}, nil),
+			})),
 			config.URLTypeWeb, false, config.CSBaseURL, "/",
 		},
 	}
@@ -296,6 +297,45 @@ func TestStoreBaseUrlandPath(t *testing.T) {
 	}
 }
 
+func TestStoreBaseURLResolver(t *testing.T) {
+	newConfig := func() *config.Manager {
+		cm := config.NewManager()
+		assert.NoError(t, cm.Write(config.Path(store.PathUnsecureBaseURL), config.Value("{{cdn}}shop")))
+		assert.NoError(t, cm.Write(config.Path(store.PathSecureBaseURL), config.Value("{{web/secure/base_url}}")))
+		return cm
+	}
+	s := store.NewStore(
+		&store.TableStore{StoreID: 1, Code: dbr.NullString{NullString: sql.NullString{String: "de", Valid: true}}, WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+		&store.TableWebsite{WebsiteID: 1, Code: dbr.NullString{NullString: sql.NullString{String: "euro", Valid: true}}, Name: dbr.NullString{NullString: sql.NullString{String: "Europe", Valid: true}}, SortOrder: 0, DefaultGroupID: 1},
+		&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 0, DefaultStoreID: 1},
+		store.SetStoreConfig(newConfig()),
+	)
+	s.Resolver().Register("cdn", func(_ config.Reader, _ ...config.ArgFunc) (string, error) {
+		return "http://cdn.corestore.io/", nil
+	})
+	assert.Exactly(t, "http://cdn.corestore.io/shop/", s.BaseURL(config.URLTypeWeb, false))
+
+	// the registered placeholder survives a new config.Reader
+	s.ApplyOptions(store.SetStoreConfig(newConfig()))
+	assert.Exactly(t, "http://cdn.corestore.io/shop/", s.BaseURL(config.URLTypeWeb, false))
+
+	// a cycle returns the unexpanded value
+	assert.Exactly(t, "{{web/secure/base_url}}/", s.BaseURL(config.URLTypeWeb, true))
+}
+
+func TestStoreAllowedCurrencies(t *testing.T) {
+	// no sections applied so the comma list must be split
+	cm := config.NewManager()
+	assert.NoError(t, cm.Write(config.Path(directory.PathSystemCurrencyInstalled), config.Value("USD,EUR,CHF")))
+	s := store.NewStore(
+		&store.TableStore{StoreID: 1, Code: dbr.NullString{NullString: sql.NullString{String: "de", Valid: true}}, WebsiteID: 1, GroupID: 1, Name: "Germany", SortOrder: 10, IsActive: true},
+		&store.TableWebsite{WebsiteID: 1, Code: dbr.NullString{NullString: sql.NullString{String: "euro", Valid: true}}, Name: dbr.NullString{NullString: sql.NullString{String: "Europe", Valid: true}}, SortOrder: 0, DefaultGroupID: 1},
+		&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", RootCategoryID: 0, DefaultStoreID: 1},
+		store.SetStoreConfig(cm),
+	)
+	assert.Exactly(t, []string{"USD", "EUR", "CHF"}, s.AllowedCurrencies())
+}
+
 func TestValidateStoreCode(t *testing.T) {
 	tests := []struct {
 		have    string
@@ -0,0 +1,162 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/juju/errgo"
+)
+
+// TrustedProxies contains the networks of the reverse proxies or load
+// balancers which terminate TLS. Only their Forwarded and X-Forwarded-Proto
+// headers will be used to detect a HTTPS request.
+type TrustedProxies struct {
+	mu   sync.RWMutex
+	nets []*net.IPNet
+}
+
+// DefaultTrustedProxies will be used by Store.RequestBaseURL(). It trusts no
+// proxy. Set the networks at the start of the app via DefaultTrustedProxies.Set().
+var DefaultTrustedProxies = NewTrustedProxies()
+
+// NewTrustedProxies creates a new empty list which trusts no proxy.
+func NewTrustedProxies() *TrustedProxies {
+	return &TrustedProxies{}
+}
+
+// Set replaces the trusted networks. An argument can be an IP address or a
+// network in CIDR notation e.g. 10.0.0.0/8 or fd00::/8.
+func (tp *TrustedProxies) Set(cidrs ...string) error {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		if false == strings.Contains(c, "/") {
+			ip := net.ParseIP(c)
+			if ip == nil {
+				return errgo.Newf("Invalid trusted proxy IP %q", c)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return errgo.Mask(err)
+		}
+		nets = append(nets, n)
+	}
+	tp.mu.Lock()
+	tp.nets = nets
+	tp.mu.Unlock()
+	return nil
+}
+
+// Contains returns true if the IP address of the remote address, with or
+// without port, belongs to a trusted network.
+func (tp *TrustedProxies) Contains(remoteAddr string) bool {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		remoteAddr = host
+	}
+	ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(remoteAddr, "["), "]"))
+	if ip == nil {
+		return false
+	}
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	for _, n := range tp.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSecure returns true if the request has been made with HTTPS. Behind a
+// trusted proxy the elements of the Forwarded header (RFC 7239) will be walked
+// from right to left as long as they have been forwarded for a trusted proxy.
+// The proto parameter of the first element forwarded for an untrusted client
+// decides. Without a Forwarded proto the X-Forwarded-Proto values will be
+// walked the same way with the addresses of X-Forwarded-For or, if both lists
+// differ in length, the last value decides.
+func (tp *TrustedProxies) IsSecure(r *http.Request) bool {
+	switch {
+	case r == nil:
+		return false
+	case r.TLS != nil:
+		return true
+	case false == tp.Contains(r.RemoteAddr):
+		return false
+	}
+	if fwd := splitHeader(r.Header, "Forwarded"); len(fwd) > 0 {
+		fors := make([]string, len(fwd))
+		for i, e := range fwd {
+			fors[i] = forwardedParam(e, "for")
+		}
+		if proto := forwardedParam(fwd[tp.clientHop(fors)], "proto"); proto != "" {
+			return strings.EqualFold(proto, "https")
+		}
+	}
+	protos := splitHeader(r.Header, "X-Forwarded-Proto")
+	if len(protos) == 0 {
+		return false
+	}
+	i := len(protos) - 1
+	if fors := splitHeader(r.Header, "X-Forwarded-For"); len(fors) == len(protos) {
+		i = tp.clientHop(fors)
+	}
+	return strings.EqualFold(protos[i], "https")
+}
+
+// clientHop walks the forwarded addresses from right to left and returns the
+// index of the first address which is not a trusted proxy or zero.
+func (tp *TrustedProxies) clientHop(fors []string) int {
+	i := len(fors) - 1
+	for i > 0 && tp.Contains(fors[i]) {
+		i--
+	}
+	return i
+}
+
+// splitHeader returns the trimmed comma separated values of all header lines
+// of key.
+func splitHeader(h http.Header, key string) []string {
+	var vals []string
+	for _, line := range h[http.CanonicalHeaderKey(key)] {
+		for _, v := range strings.Split(line, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				vals = append(vals, v)
+			}
+		}
+	}
+	return vals
+}
+
+// forwardedParam returns the parameter key of an element of a Forwarded
+// header e.g. for=192.0.2.60;proto=https;by=203.0.113.43
+func forwardedParam(elem, key string) string {
+	for _, pair := range strings.Split(elem, ";") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) == 2 && strings.EqualFold(kv[0], key) {
+			return strings.Trim(kv[1], `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+// Copyright 2015, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func getProxyRequest(t *testing.T, u, remoteAddr string, header ...string) *http.Request {
+	req := getTestRequest(t, "GET", u, nil)
+	req.RemoteAddr = remoteAddr
+	for i := 0; i < len(header); i = i + 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	return req
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tp := store.NewTrustedProxies()
+	assert.EqualError(t, tp.Set("10.0.0.256"), `Invalid trusted proxy IP "10.0.0.256"`)
+	assert.Error(t, tp.Set("10.0.0.0/33"))
+	assert.NoError(t, tp.Set("10.0.0.0/8", "192.168.1.1", "fd00::/8"))
+
+	tests := []struct {
+		req  *http.Request
+		want bool
+	}{
+		{nil, false},
+		{getProxyRequest(t, "http://cs.io", "10.1.2.3:4711"), false},
+		{getProxyRequest(t, "http://cs.io", "10.1.2.3:4711", "X-Forwarded-Proto", "https"), true},
+		{getProxyRequest(t, "http://cs.io", "10.1.2.3:4711", "X-Forwarded-Proto", "HTTPS, http"), false},
+		{getProxyRequest(t, "http://cs.io", "10.1.2.3:4711", "X-Forwarded-Proto", "http, HTTPS"), true},
+		{getProxyRequest(t, "http://cs.io", "10.1.2.3:4711", "X-Forwarded-Proto", "https, http", "X-Forwarded-For", "192.0.2.60, 10.1.2.4"), true},
+		{getProxyRequest(t, "http://cs.io", "10.1.2.3:4711", "X-Forwarded-Proto", "https, http", "X-Forwarded-For", "10.1.2.4, 192.0.2.60"), false},
+		{getProxyRequest(t, "http://cs.io", "192.168.1.1:4711", "Forwarded", `for=192.0.2.60;proto="https";by=10.1.2.3`), true},
+		{getProxyRequest(t, "http://cs.io", "192.168.1.1:4711", "Forwarded", "for=192.0.2.60;proto=http, for=10.1.2.3;proto=https", "X-Forwarded-Proto", "https"), false},
+		{getProxyRequest(t, "http://cs.io", "192.168.1.1:4711", "Forwarded", "for=192.0.2.60;proto=https, for=10.1.2.3;proto=http"), true},
+		{getProxyRequest(t, "http://cs.io", "192.168.1.1:4711", "Forwarded", `for=192.0.2.60;proto=https, for="[fd12::1]:4711";proto=http`), true},
+		{getProxyRequest(t, "http://cs.io", "192.168.1.1:4711", "Forwarded", "for=10.1.2.3;proto=https, for=192.0.2.60;proto=http"), false}, // spoofed by the client
+		{getProxyRequest(t, "http://cs.io", "192.168.1.1:4711", "Forwarded", "for=192.0.2.60", "X-Forwarded-Proto", "https"), true},
+		{getProxyRequest(t, "http://cs.io", "[fd12::1]:4711", "X-Forwarded-Proto", "https"), true},
+		{getProxyRequest(t, "http://cs.io", "192.168.1.2:4711", "X-Forwarded-Proto", "https"), false},
+		{getProxyRequest(t, "http://cs.io", "192.0.2.60:4711", "Forwarded", "proto=https"), false},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.want, tp.IsSecure(test.req), "Index %d", i)
+	}
+
+	req := getProxyRequest(t, "https://cs.io", "192.0.2.60:4711")
+	req.TLS = &tls.ConnectionState{}
+	assert.True(t, tp.IsSecure(req))
+
+	// the proxy appends its element as a new header line
+	req = getProxyRequest(t, "http://cs.io", "10.1.2.3:4711", "Forwarded", "for=192.0.2.60;proto=https")
+	req.Header.Add("Forwarded", "for=192.0.2.61;proto=http")
+	assert.False(t, tp.IsSecure(req))
+
+	assert.NoError(t, tp.Set())
+	assert.False(t, tp.IsSecure(getProxyRequest(t, "http://cs.io", "10.1.2.3:4711", "X-Forwarded-Proto", "https")))
+}
+
+func TestStoreRequestBaseURL(t *testing.T) {
+	assert.NoError(t, store.DefaultTrustedProxies.Set("10.0.0.0/8"))
+	defer store.DefaultTrustedProxies.Set()
+	store.DefaultAllowedHosts.Set("shop.io", "Shop.CH")
+	defer store.DefaultAllowedHosts.Set()
+
+	cm := config.NewManager()
+	for _, w := range []struct {
+		path, value string
+		scope       config.ArgFunc
+	}{
+		{config.PathCSBaseURL, "http://shop.io/", config.Scope(config.ScopeDefaultID, nil)},
+		{store.PathUnsecureBaseURL, store.PlaceholderBaseURL, config.Scope(config.ScopeDefaultID, nil)},
+		{store.PathSecureBaseURL, "https://secure.cs.io/", config.Scope(config.ScopeDefaultID, nil)},
+		{store.PathUnsecureBaseStaticURL, store.PlaceholderBaseURLUnSecure + "static/", config.Scope(config.ScopeDefaultID, nil)},
+		{store.PathSecureBaseStaticURL, store.PlaceholderBaseURLSecure + "static/", config.Scope(config.ScopeDefaultID, nil)},
+		{store.PathSecureInFrontend, "1", config.ScopeStore(config.ScopeID(1))},
+	} {
+		assert.NoError(t, cm.Write(config.Path(w.path), config.Value(w.value), w.scope, config.NoBubble()))
+	}
+	de := store.NewStore(
+		&store.TableStore{StoreID: 1, Code: nullString("de"), WebsiteID: 1, GroupID: 1, Name: "Germany", IsActive: true},
+		&store.TableWebsite{WebsiteID: 1, Code: nullString("euro"), Name: nullString("Europe"), DefaultGroupID: 1},
+		&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", DefaultStoreID: 1},
+		store.SetStoreConfig(cm),
+	)
+	at := store.NewStore(
+		&store.TableStore{StoreID: 2, Code: nullString("at"), WebsiteID: 1, GroupID: 1, Name: "Österreich", IsActive: true},
+		&store.TableWebsite{WebsiteID: 1, Code: nullString("euro"), Name: nullString("Europe"), DefaultGroupID: 1},
+		&store.TableGroup{GroupID: 1, WebsiteID: 1, Name: "DACH Group", DefaultStoreID: 1},
+		store.SetStoreConfig(cm),
+	)
+	tlsReq := getProxyRequest(t, "https://shop.io/catalog", "192.0.2.60:4711")
+	tlsReq.TLS = &tls.ConnectionState{}
+	poisonedReq := getProxyRequest(t, "https://evil.io/catalog", "192.0.2.60:4711")
+	poisonedReq.TLS = &tls.ConnectionState{}
+	chReq := getProxyRequest(t, "https://shop.ch/catalog", "192.0.2.60:4711")
+	chReq.TLS = &tls.ConnectionState{}
+
+	tests := []struct {
+		s          *store.Store
+		req        *http.Request
+		wantWeb    string
+		wantStatic string
+	}{
+		{de, getProxyRequest(t, "http://shop.io/catalog", "10.1.2.3:4711"), "http://shop.io/", "http://shop.io/static/"},
+		{de, getProxyRequest(t, "http://shop.io:8080/", "192.0.2.60:4711", "X-Forwarded-Proto", "https"), "http://shop.io/", "http://shop.io/static/"},
+		{de, getProxyRequest(t, "http://shop.io/", "10.1.2.3:4711", "X-Forwarded-Proto", "https"), "https://secure.cs.io/", "https://secure.cs.io/static/"},
+		{de, tlsReq, "https://secure.cs.io/", "https://secure.cs.io/static/"},
+		// secure URLs are not enabled in the frontend of store at
+		{at, tlsReq, "https://shop.io/", "https://shop.io/static/"},
+		// the host of the request has not been allowed
+		{at, poisonedReq, "http://shop.io/", "http://shop.io/static/"},
+		// {{base_url}} takes an allowed host which differs from web/corestore/base_url
+		{at, chReq, "https://shop.ch/", "https://shop.ch/static/"},
+		{at, getProxyRequest(t, "http://shop.ch/", "192.0.2.60:4711"), "http://shop.ch/", "http://shop.ch/static/"},
+		{at, getProxyRequest(t, "http://shop.ch:8080/", "192.0.2.60:4711"), "http://shop.io/", "http://shop.io/static/"},
+	}
+	for i, test := range tests {
+		assert.Exactly(t, test.wantWeb, test.s.RequestBaseURL(test.req, config.URLTypeWeb), "Index %d", i)
+		assert.Exactly(t, test.wantStatic, test.s.RequestBaseURL(test.req, config.URLTypeStatic), "Index %d", i)
+	}
+
+	// the placeholder of the Store remains unchanged
+	assert.Exactly(t, "http://shop.io/", de.BaseURL(config.URLTypeWeb, false))
+}